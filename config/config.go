@@ -5,6 +5,10 @@
 package config
 
 import (
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog/log"
 )
@@ -15,16 +19,48 @@ const DefaultEnterpriseApiBaseUrl = "https://api.gatling.io/api/public"
 // through environment variables. Learn more through the documentation of the envconfig package.
 // https://github.com/kelseyhightower/envconfig
 type Specification struct {
-	KubernetesClusterName                  string `json:"kubernetesClusterName" split_words:"true" required:"false"`
-	KubernetesNodeName                     string `json:"kubernetesNodeName" split_words:"true" required:"false"`
-	KubernetesPodName                      string `json:"kubernetesPodName" split_words:"true" required:"false"`
-	KubernetesNamespace                    string `json:"kubernetesNamespace" split_words:"true" required:"false"`
-	EnableLocationSelection                bool   `json:"enableLocationSelection" split_words:"true" required:"false" default:"true"`
-	EnterpriseApiToken                     string `json:"enterpriseApiToken" split_words:"true" required:"false"`
-	EnterpriseApiBaseUrl                   string `json:"enterpriseApiBaseUrl" split_words:"true" required:"false" default:"https://api.gatling.io/api/public"`
-	EnterpriseOrganizationSlug             string `json:"enterpriseOrganizationSlug" split_words:"true" required:"false" default:"your-organization-slug"`
-	EnterpriseSimulationsDiscoveryInterval string `json:"enterpriseSimulationsDiscoveryInterval" split_words:"true" required:"false" default:"3h"`
-	InsecureSkipVerify                     bool   `json:"insecureSkipVerify" split_words:"true" default:"false"`
+	KubernetesClusterName                  string            `json:"kubernetesClusterName" split_words:"true" required:"false"`
+	KubernetesNodeName                     string            `json:"kubernetesNodeName" split_words:"true" required:"false"`
+	KubernetesPodName                      string            `json:"kubernetesPodName" split_words:"true" required:"false"`
+	KubernetesNamespace                    string            `json:"kubernetesNamespace" split_words:"true" required:"false"`
+	EnableLocationSelection                bool              `json:"enableLocationSelection" split_words:"true" required:"false" default:"true"`
+	EnterpriseApiToken                     string            `json:"enterpriseApiToken" split_words:"true" required:"false"`
+	EnterpriseApiBaseUrl                   string            `json:"enterpriseApiBaseUrl" split_words:"true" required:"false" default:"https://api.gatling.io/api/public"`
+	EnterpriseOrganizationSlug             string            `json:"enterpriseOrganizationSlug" split_words:"true" required:"false" default:"your-organization-slug"`
+	EnterpriseSimulationsDiscoveryInterval string            `json:"enterpriseSimulationsDiscoveryInterval" split_words:"true" required:"false" default:"3h"`
+	InsecureSkipVerify                     bool              `json:"insecureSkipVerify" split_words:"true" default:"false"`
+	MinFreeDiskSpaceMb                     int64             `json:"minFreeDiskSpaceMb" split_words:"true" required:"false" default:"1024"`
+	MaxReportSizeMb                        int64             `json:"maxReportSizeMb" split_words:"true" required:"false" default:"4096"`
+	MaxArtifactSizeMb                      int64             `json:"maxArtifactSizeMb" split_words:"true" required:"false" default:"32"`
+	ReportRetention                        time.Duration     `json:"reportRetention" split_words:"true" required:"false" default:"24h"`
+	ReportsBaseUrl                         string            `json:"reportsBaseUrl" split_words:"true" required:"false"`
+	S3Endpoint                             string            `json:"s3Endpoint" split_words:"true" required:"false"`
+	S3Bucket                               string            `json:"s3Bucket" split_words:"true" required:"false"`
+	S3Region                               string            `json:"s3Region" split_words:"true" required:"false"`
+	S3AccessKeyId                          string            `json:"s3AccessKeyId" split_words:"true" required:"false"`
+	S3SecretAccessKey                      string            `json:"s3SecretAccessKey" split_words:"true" required:"false"`
+	S3UseSsl                               bool              `json:"s3UseSsl" split_words:"true" required:"false" default:"true"`
+	S3KeyLayout                            string            `json:"s3KeyLayout" split_words:"true" required:"false" default:"{experimentKey}/{executionId}/"`
+	S3PublicBaseUrl                        string            `json:"s3PublicBaseUrl" split_words:"true" required:"false"`
+	WebhookUrls                            []string          `json:"webhookUrls" split_words:"true" required:"false"`
+	WebhookFormat                          string            `json:"webhookFormat" split_words:"true" required:"false" default:"json"`
+	WebhookRetries                         int               `json:"webhookRetries" split_words:"true" required:"false" default:"3"`
+	TracingEnabled                         bool              `json:"tracingEnabled" split_words:"true" required:"false" default:"false"`
+	OtlpMetricsEndpoint                    string            `json:"otlpMetricsEndpoint" split_words:"true" required:"false"`
+	LiveMetricsSource                      string            `json:"liveMetricsSource" split_words:"true" required:"false" default:"graphite"`
+	BaselineDir                            string            `json:"baselineDir" split_words:"true" required:"false" default:"/tmp/steadybit-baselines"`
+	AggregationUrl                         string            `json:"aggregationUrl" split_words:"true" required:"false"`
+	AggregationToken                       string            `json:"aggregationToken" split_words:"true" required:"false"`
+	LocationWeight                         float64           `json:"locationWeight" split_words:"true" required:"false" default:"1"`
+	LocationRegion                         string            `json:"locationRegion" split_words:"true" required:"false"`
+	LocationZone                           string            `json:"locationZone" split_words:"true" required:"false"`
+	LocationLabels                         map[string]string `json:"locationLabels" split_words:"true" required:"false"`
+	MaxConcurrentRuns                      int               `json:"maxConcurrentRuns" split_words:"true" required:"false" default:"0"`
+	RunQueueTimeout                        time.Duration     `json:"runQueueTimeout" split_words:"true" required:"false" default:"0s"`
+	JvmHeap                                string            `json:"jvmHeap" split_words:"true" required:"false"`
+	JvmOptions                             string            `json:"jvmOptions" split_words:"true" required:"false"`
+	RunMemoryLimitMb                       int64             `json:"runMemoryLimitMb" split_words:"true" required:"false" default:"0"`
+	RunCpuLimit                            float64           `json:"runCpuLimit" split_words:"true" required:"false" default:"0"`
 }
 
 var (
@@ -39,5 +75,16 @@ func ParseConfiguration() {
 }
 
 func ValidateConfiguration() {
-	// You may optionally validate the configuration here.
+	if Config.AggregationUrl != "" && Config.AggregationToken == "" {
+		log.Fatal().Msgf("STEADYBIT_EXTENSION_AGGREGATION_URL requires STEADYBIT_EXTENSION_AGGREGATION_TOKEN to be set.")
+	}
+	if Config.ReportRetention < 0 {
+		log.Fatal().Msgf("STEADYBIT_EXTENSION_REPORT_RETENTION must not be negative, got %s.", Config.ReportRetention)
+	}
+	if Config.RunQueueTimeout < 0 {
+		log.Fatal().Msgf("STEADYBIT_EXTENSION_RUN_QUEUE_TIMEOUT must not be negative, got %s.", Config.RunQueueTimeout)
+	}
+	if dir := filepath.Clean(Config.BaselineDir); dir == "/tmp" || strings.HasPrefix(dir, "/tmp/") {
+		log.Warn().Msgf("STEADYBIT_EXTENSION_BASELINE_DIR %s is below /tmp, baselines are lost when the extension restarts. Point it to a persistent volume to keep them.", Config.BaselineDir)
+	}
 }
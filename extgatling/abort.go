@@ -0,0 +1,101 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// defaultAbortAfter is how long a criterion has to be exceeded when the
+// action doesn't set "Abort After", matching the default of the parameter.
+const defaultAbortAfter = 30 * time.Second
+
+// abortCriteria stop a local run early once the live statistics exceed a
+// threshold for a while. Unset thresholds are nil.
+type abortCriteria struct {
+	MaxErrorPercentage *float64 `json:"maxErrorPercentage,omitempty"`
+	MaxP99             *float64 `json:"maxP99,omitempty"` // milliseconds
+	For                int64    `json:"for"`              // milliseconds
+	// since when a criterion is exceeded, epoch millis by criterion and
+	// simulation
+	ExceededSince map[string]int64 `json:"exceededSince,omitempty"`
+}
+
+// parseAbortCriteria builds the abort criteria from the parameters of the
+// action, nil if none is set.
+func parseAbortCriteria(config GatlingLoadTestRunConfig) *abortCriteria {
+	criteria := &abortCriteria{
+		MaxErrorPercentage: config.AbortMaxErrorPercentage,
+		MaxP99:             durationMillis(config.AbortMaxP99),
+		For:                defaultAbortAfter.Milliseconds(),
+	}
+	if criteria.MaxErrorPercentage == nil && criteria.MaxP99 == nil {
+		return nil
+	}
+	if config.AbortAfter != nil && *config.AbortAfter > 0 {
+		criteria.For = int64(*config.AbortAfter)
+	}
+	return criteria
+}
+
+// check evaluates the criteria against the live statistics of the run's
+// simulations. Returns what tripped, empty if the run may go on.
+func (c *abortCriteria) check(stats map[string]liveStats, now time.Time) string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exceeded := map[string]int64{}
+	tripped := ""
+	for _, name := range names {
+		s := stats[name]
+		if c.MaxErrorPercentage != nil && s.Requests > 0 && s.ErrorRatio*100 > *c.MaxErrorPercentage {
+			if c.exceeded(exceeded, "errors/"+name, now) && tripped == "" {
+				tripped = fmt.Sprintf("error percentage of %s was above %s%% for %s, last at %s%%",
+					name, formatNumber(*c.MaxErrorPercentage), c.forDuration(), formatNumber(s.ErrorRatio*100))
+			}
+		}
+		if c.MaxP99 != nil && !math.IsNaN(s.P99) && s.P99 > *c.MaxP99 {
+			if c.exceeded(exceeded, "p99/"+name, now) && tripped == "" {
+				tripped = fmt.Sprintf("p99 response time of %s was above %s ms for %s, last at %s ms",
+					name, formatNumber(*c.MaxP99), c.forDuration(), formatNumber(s.P99))
+			}
+		}
+	}
+	// criteria no longer exceeded start over
+	c.ExceededSince = exceeded
+	return tripped
+}
+
+// exceeded records a criterion as exceeded and reports whether it has been
+// for long enough.
+func (c *abortCriteria) exceeded(exceeded map[string]int64, key string, now time.Time) bool {
+	since, ok := c.ExceededSince[key]
+	if !ok {
+		since = now.UnixMilli()
+	}
+	exceeded[key] = since
+	return now.UnixMilli()-since >= c.For
+}
+
+func (c *abortCriteria) forDuration() time.Duration {
+	return time.Duration(c.For) * time.Millisecond
+}
+
+// abortError fails the step of a run stopped by an abort criterion.
+func abortError(reason string) *action_kit_api.ActionKitError {
+	return &action_kit_api.ActionKitError{
+		Status: extutil.Ptr(action_kit_api.Failed),
+		Title:  fmt.Sprintf("Gatling run aborted, the %s.", reason),
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/extwebhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAbortCriteria(t *testing.T) {
+	assert.Nil(t, parseAbortCriteria(GatlingLoadTestRunConfig{AbortAfter: new(30000)}))
+
+	criteria := parseAbortCriteria(GatlingLoadTestRunConfig{AbortMaxP99: new(2000), AbortAfter: new(30000)})
+
+	require.NotNil(t, criteria)
+	assert.InDelta(t, 2000, *criteria.MaxP99, 0.001)
+	assert.Nil(t, criteria.MaxErrorPercentage)
+	assert.Equal(t, int64(30000), criteria.For)
+}
+
+func Test_parseAbortCriteria_defaults_the_time_to_exceed(t *testing.T) {
+	criteria := parseAbortCriteria(GatlingLoadTestRunConfig{AbortMaxErrorPercentage: new(5.0)})
+
+	require.NotNil(t, criteria)
+	assert.Equal(t, int64(30000), criteria.For)
+}
+
+func Test_abortCriteria_trips_once_exceeded_for_long_enough(t *testing.T) {
+	criteria := &abortCriteria{MaxErrorPercentage: new(5.0), MaxP99: new(1000.0), For: 10000}
+	start := time.Unix(1767268800, 0)
+	failing := map[string]liveStats{"basicsimulation": {Requests: 100, ErrorRatio: 0.2, P99: 300}}
+
+	assert.Empty(t, criteria.check(failing, start))
+	assert.Empty(t, criteria.check(failing, start.Add(5*time.Second)))
+	assert.Equal(t, "error percentage of basicsimulation was above 5% for 10s, last at 20%",
+		criteria.check(failing, start.Add(10*time.Second)))
+}
+
+func Test_abortCriteria_start_over_once_recovered(t *testing.T) {
+	criteria := &abortCriteria{MaxP99: new(1000.0), For: 10000}
+	start := time.Unix(1767268800, 0)
+	slow := map[string]liveStats{"basicsimulation": {Requests: 100, P99: 1500}}
+	idle := map[string]liveStats{"basicsimulation": {P99: math.NaN()}}
+
+	assert.Empty(t, criteria.check(slow, start))
+	assert.Empty(t, criteria.check(idle, start.Add(5*time.Second)))
+	assert.Empty(t, criteria.check(slow, start.Add(10*time.Second)))
+	assert.Empty(t, criteria.check(slow, start.Add(15*time.Second)))
+	assert.Equal(t, "p99 response time of basicsimulation was above 1000 ms for 10s, last at 1500 ms",
+		criteria.check(slow, start.Add(20*time.Second)))
+}
+
+func Test_abortError(t *testing.T) {
+	err := abortError("p99 response time of basicsimulation was above 1000 ms for 10s, last at 1500 ms")
+
+	assert.Equal(t, action_kit_api.Failed, *err.Status)
+	assert.Equal(t, "Gatling run aborted, the p99 response time of basicsimulation was above 1000 ms for 10s, last at 1500 ms.", err.Title)
+}
+
+func Test_runResult_reports_the_abort_criterion_over_the_exit_code(t *testing.T) {
+	state := &GatlingLoadTestRunState{AbortedBy: "p99 response time of basicsimulation was above 1000 ms for 10s, last at 1500 ms"}
+
+	for _, exitCode := range []int{0, 1, 2, 130, 137} {
+		err, status := runResult(state, exitCode)
+
+		require.NotNil(t, err, "exit code %d", exitCode)
+		assert.Equal(t, "Gatling run aborted, the p99 response time of basicsimulation was above 1000 ms for 10s, last at 1500 ms.", err.Title)
+		assert.Equal(t, extwebhook.StatusFailed, status)
+	}
+}
+
+func Test_runResult_without_abort(t *testing.T) {
+	err, status := runResult(&GatlingLoadTestRunState{}, 130)
+	assert.Nil(t, err)
+	assert.Equal(t, extwebhook.StatusStopped, status)
+
+	err, status = runResult(&GatlingLoadTestRunState{}, 1)
+	require.NotNil(t, err)
+	assert.Equal(t, "Gatling run errored, exit-code 1", err.Title)
+	assert.Equal(t, extwebhook.StatusErrored, status)
+}
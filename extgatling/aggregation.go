@@ -0,0 +1,277 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// AggregationPath is the path the locations push their simulation.log to, as
+// /aggregation/<experimentKey>/<executionId>/<source>.log.
+const AggregationPath = "/aggregation/"
+
+// aggregationRoot holds the pushed simulation.log files until they are merged,
+// by experiment key and execution id.
+var aggregationRoot = "/tmp/steadybit-aggregation"
+
+// aggregationRetention is how long pushed simulation.log files are kept if
+// they are never merged.
+const aggregationRetention = 24 * time.Hour
+
+var (
+	aggregationSegment = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`) // no leading dot, no ".."
+	unsafeSourceChars  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+var errPushTooLarge = errors.New("simulation.log exceeds the report size limit")
+
+// aggregationSource names this extension instance in the file names of the
+// pushed simulation.log files, by its pod name or host name.
+func aggregationSource() string {
+	source := config.Config.KubernetesPodName
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	source = strings.TrimLeft(unsafeSourceChars.ReplaceAllString(source, "-"), ".")
+	if source == "" {
+		return "unknown"
+	}
+	return source
+}
+
+// pushSimulationLog sends the simulation.log of the report in reportDir to
+// the configured aggregation, gzip-compressed, until ctx is done. A failed push
+// doesn't fail the step, it only leaves the report out of the merged one.
+func pushSimulationLog(ctx context.Context, state *GatlingLoadTestRunState, reportDir string) []action_kit_api.Message {
+	name := filepath.Base(reportDir)
+	target := fmt.Sprintf("%s%s%s/%d/%s.log", strings.TrimSuffix(config.Config.AggregationUrl, "/"), AggregationPath,
+		url.PathEscape(state.ExperimentKey), state.ExperimentExecutionId, url.PathEscape(aggregationSource()+"_"+name))
+	if err := putSimulationLog(ctx, target, filepath.Join(reportDir, "simulation.log")); err != nil {
+		log.Warn().Err(err).Msgf("Failed to push the simulation.log of report %s to %s", name, target)
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Failed to push the simulation.log of report %s to the aggregation, it is left out of the merged report: %s", name, err),
+		}}
+	}
+	log.Info().Msgf("Pushed the simulation.log of report %s to %s", name, target)
+	return []action_kit_api.Message{{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("simulation.log of report %s pushed to the aggregation", name),
+	}}
+}
+
+func putSimulationLog(ctx context.Context, target, simulationLog string) error {
+	file, err := os.Open(simulationLog)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	// compresses while sending, so the log is never held in memory
+	body, pipe := io.Pipe()
+	defer func() { _ = body.Close() }()
+	go func() {
+		writer := gzip.NewWriter(pipe)
+		_, err := io.Copy(writer, file)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		_ = pipe.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
+	setAggregationToken(req)
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("aggregation responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// setAggregationToken authenticates a request to the aggregation with the
+// configured token.
+func setAggregationToken(req *http.Request) {
+	if token := config.Config.AggregationToken; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// authorizedForAggregation checks the token of a request to the aggregation
+// or the barrier. Without a configured token, all requests are refused.
+func authorizedForAggregation(w http.ResponseWriter, r *http.Request) bool {
+	token := config.Config.AggregationToken
+	if token == "" {
+		http.Error(w, "aggregation disabled, STEADYBIT_EXTENSION_AGGREGATION_TOKEN is not set", http.StatusForbidden)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+		http.Error(w, "invalid aggregation token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// ServeAggregation stores the simulation.log pushed by a location for
+// merging. Files pushed again under the same name replace the earlier one.
+// It streams the body to disk, so it is registered as a plain http.Handler
+// instead of an exthttp handler, which reads the whole body into memory.
+func ServeAggregation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !authorizedForAggregation(w, r) {
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, AggregationPath), "/")
+	if len(segments) != 3 || !aggregationSegment.MatchString(segments[0]) || !aggregationSegment.MatchString(segments[2]) ||
+		!strings.HasSuffix(segments[2], ".log") {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := strconv.Atoi(segments[1]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var content io.Reader = r.Body
+	if maxSizeMb := config.Config.MaxReportSizeMb; maxSizeMb > 0 {
+		content = http.MaxBytesReader(w, r.Body, maxSizeMb*megabyte)
+	}
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(content)
+		if err != nil {
+			http.Error(w, "invalid gzip body", http.StatusBadRequest)
+			return
+		}
+		content = reader
+	}
+
+	pruneAggregatedLogs(aggregationRetention)
+	executionDir := filepath.Join(aggregationRoot, segments[0], segments[1])
+	if err := storeAggregatedLog(executionDir, segments[2], content); err != nil {
+		log.Warn().Err(err).Msgf("Failed to store pushed %s of %s execution %s", segments[2], segments[0], segments[1])
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errPushTooLarge) || errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Info().Msgf("Received %s of %s execution %s", segments[2], segments[0], segments[1])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// storeAggregatedLog writes content to name in executionDir, via a temporary
+// file so a merge never picks up a partial one. Content beyond the report
+// size limit is refused.
+func storeAggregatedLog(executionDir, name string, content io.Reader) error {
+	if err := os.MkdirAll(executionDir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(executionDir, ".push-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if maxSizeMb := config.Config.MaxReportSizeMb; maxSizeMb > 0 {
+		content = io.LimitReader(content, maxSizeMb*megabyte+1)
+	}
+	written, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if maxSizeMb := config.Config.MaxReportSizeMb; maxSizeMb > 0 && written > maxSizeMb*megabyte {
+		return fmt.Errorf("%w of %d MB", errPushTooLarge, maxSizeMb)
+	}
+	return os.Rename(file.Name(), filepath.Join(executionDir, name))
+}
+
+// aggregatedLogs returns the paths of the simulation.log files pushed for an
+// experiment execution, sorted by name.
+func aggregatedLogs(experimentKey string, executionId int) ([]string, error) {
+	executionDir := filepath.Join(aggregationRoot, experimentKey, strconv.Itoa(executionId))
+	entries, err := os.ReadDir(executionDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var logs []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".log") {
+			logs = append(logs, filepath.Join(executionDir, entry.Name()))
+		}
+	}
+	sort.Strings(logs)
+	return logs, nil
+}
+
+// pruneAggregatedLogs removes the pushed simulation.log files of all
+// executions that are older than the retention.
+func pruneAggregatedLogs(retention time.Duration) {
+	experiments, err := os.ReadDir(aggregationRoot)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read the aggregated simulation logs")
+		}
+		return
+	}
+	for _, experiment := range experiments {
+		experimentDir := filepath.Join(aggregationRoot, experiment.Name())
+		executions, err := os.ReadDir(experimentDir)
+		if err != nil {
+			continue
+		}
+		for _, execution := range executions {
+			info, err := execution.Info()
+			if err != nil || time.Since(info.ModTime()) <= retention {
+				continue
+			}
+			log.Debug().Msgf("Removing expired simulation logs of %s execution %s", experiment.Name(), execution.Name())
+			if err := os.RemoveAll(filepath.Join(experimentDir, execution.Name())); err != nil {
+				log.Warn().Err(err).Msgf("Failed to remove expired simulation logs of %s execution %s", experiment.Name(), execution.Name())
+			}
+		}
+		// only removes the experiment directory if it is empty by now
+		_ = os.Remove(experimentDir)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pushSimulationLog_stores_it_for_merging(t *testing.T) {
+	useAggregationRoot(t)
+	useAggregationToken(t)
+	server := httptest.NewServer(http.HandlerFunc(ServeAggregation))
+	defer server.Close()
+	config.Config.AggregationUrl = server.URL + "/"
+	config.Config.KubernetesPodName = "extension-gatling-0"
+	defer func() {
+		config.Config.AggregationUrl = ""
+		config.Config.KubernetesPodName = ""
+	}()
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), "binary log")
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	messages := pushSimulationLog(context.Background(), state, reportDir)
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, action_kit_api.Info, *messages[0].Level)
+	logs, err := aggregatedLogs("ADM-1", 42)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(aggregationRoot, "ADM-1", "42", "extension-gatling-0_basicsimulation-20260101120000123.log")}, logs)
+	content, err := os.ReadFile(logs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "binary log", string(content))
+}
+
+func Test_pushSimulationLog_warns_if_the_aggregation_fails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	config.Config.AggregationUrl = server.URL
+	defer func() { config.Config.AggregationUrl = "" }()
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), "binary log")
+
+	messages := pushSimulationLog(context.Background(), &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}, reportDir)
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, action_kit_api.Warn, *messages[0].Level)
+	assert.Contains(t, messages[0].Message, "status 500")
+}
+
+func Test_pushSimulationLog_stops_at_the_deadline_of_stop(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	config.Config.AggregationUrl = server.URL
+	defer func() { config.Config.AggregationUrl = "" }()
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), "binary log")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	messages := pushSimulationLog(ctx, &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}, reportDir)
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, action_kit_api.Warn, *messages[0].Level)
+	assert.Contains(t, messages[0].Message, "deadline exceeded")
+}
+
+func Test_ServeAggregation_refuses_invalid_paths(t *testing.T) {
+	useAggregationRoot(t)
+	useAggregationToken(t)
+	put := func(path string) int {
+		recorder := httptest.NewRecorder()
+		ServeAggregation(recorder, aggregationRequest(http.MethodPut, path, strings.NewReader("log")))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, put("/aggregation/ADM-1/42/location_report.log"))
+	assert.Equal(t, http.StatusNotFound, put("/aggregation/ADM-1/42/location_report.txt"))
+	assert.Equal(t, http.StatusNotFound, put("/aggregation/ADM-1/latest/location_report.log"))
+	assert.Equal(t, http.StatusNotFound, put("/aggregation/../42/location_report.log"))
+	assert.Equal(t, http.StatusNotFound, put("/aggregation/ADM-1/42/sub/location_report.log"))
+
+	recorder := httptest.NewRecorder()
+	ServeAggregation(recorder, aggregationRequest(http.MethodGet, "/aggregation/ADM-1/42/location_report.log", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func Test_ServeAggregation_requires_the_token(t *testing.T) {
+	useAggregationRoot(t)
+	put := func(authorization string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/aggregation/ADM-1/42/location_report.log", strings.NewReader("log"))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		ServeAggregation(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, put("Bearer "), "without a configured token")
+	useAggregationToken(t)
+	assert.Equal(t, http.StatusUnauthorized, put(""))
+	assert.Equal(t, http.StatusUnauthorized, put("Bearer wrong"))
+	assert.Equal(t, http.StatusNoContent, put("Bearer "+testAggregationToken))
+}
+
+func Test_ServeAggregation_limits_the_size(t *testing.T) {
+	useAggregationRoot(t)
+	useAggregationToken(t)
+	config.Config.MaxReportSizeMb = 1
+	defer func() { config.Config.MaxReportSizeMb = 4096 }()
+
+	recorder := httptest.NewRecorder()
+	ServeAggregation(recorder, aggregationRequest(http.MethodPut, "/aggregation/ADM-1/42/location_report.log",
+		strings.NewReader(strings.Repeat("x", megabyte+1))))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	logs, err := aggregatedLogs("ADM-1", 42)
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func Test_pruneAggregatedLogs_removes_expired_executions(t *testing.T) {
+	useAggregationRoot(t)
+	writeFile(t, filepath.Join(aggregationRoot, "ADM-1", "42", "location_report.log"), "log")
+
+	pruneAggregatedLogs(time.Hour)
+	assert.DirExists(t, filepath.Join(aggregationRoot, "ADM-1", "42"))
+
+	pruneAggregatedLogs(0)
+	assert.NoDirExists(t, filepath.Join(aggregationRoot, "ADM-1"))
+}
+
+const testAggregationToken = "secret"
+
+func useAggregationRoot(t *testing.T) {
+	t.Helper()
+	previous := aggregationRoot
+	aggregationRoot = t.TempDir()
+	t.Cleanup(func() { aggregationRoot = previous })
+}
+
+func useAggregationToken(t *testing.T) {
+	t.Helper()
+	config.Config.AggregationToken = testAggregationToken
+	t.Cleanup(func() { config.Config.AggregationToken = "" })
+}
+
+func aggregationRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer "+testAggregationToken)
+	return req
+}
@@ -18,7 +18,14 @@ import (
 // zipDir writes the contents of dir into a newly created zip archive at dst, with
 // paths relative to dir. dst must not be inside dir. Anything that is not a
 // regular file or a directory is skipped.
-func zipDir(dir, dst string) (err error) {
+func zipDir(dir, dst string) error {
+	return zipDirFiltered(dir, dst, nil)
+}
+
+// zipDirFiltered works like zipDir, but only adds the files for which include
+// returns true. include gets the slash separated path relative to dir and is not
+// consulted for directories. A nil include adds everything.
+func zipDirFiltered(dir, dst string, include func(name string) bool) (err error) {
 	out, err := os.Create(dst)
 	if err != nil {
 		return err
@@ -54,6 +61,9 @@ func zipDir(dir, dst string) (err error) {
 			log.Warn().Msgf("Not adding %s to %s: not a regular file", path, dst)
 			return nil
 		}
+		if include != nil && !include(name) {
+			return nil
+		}
 
 		in, err := os.Open(path)
 		if err != nil {
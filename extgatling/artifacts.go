@@ -0,0 +1,156 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// Artifact modes of the local action, selecting what Stop attaches for each
+// report.
+const (
+	artifactModeFull          = "full"
+	artifactModeSummary       = "summary"
+	artifactModeSimulationLog = "simulation-log"
+	artifactModeNone          = "none"
+)
+
+// reportArtifacts returns the artifacts to attach for the report in reportDir
+// according to mode, plus the warnings to show if some had to be left out. The
+// summary includes the statistics by phase, if computed.
+func reportArtifacts(reportDir, mode string, phases []phaseSummary) ([]action_kit_api.Artifact, []action_kit_api.Message, error) {
+	switch mode {
+	case artifactModeNone:
+		return nil, nil, nil
+	case artifactModeSummary:
+		return summaryArtifact(reportDir, phases)
+	case artifactModeSimulationLog:
+		return simulationLogArtifact(reportDir)
+	default:
+		return reportZipArtifact(reportDir)
+	}
+}
+
+func reportZipArtifact(reportDir string) ([]action_kit_api.Artifact, []action_kit_api.Message, error) {
+	name := filepath.Base(reportDir)
+	zippedReport := reportDir + ".zip"
+	log.Info().Msgf("Zipping report %s to %s", name, zippedReport)
+
+	var messages []action_kit_api.Message
+	trimmed, err := zipReport(reportDir, zippedReport, config.Config.MaxArtifactSizeMb)
+	if errors.Is(err, errArtifactTooLarge) {
+		log.Warn().Msgf("Report %s exceeds the artifact size limit, not attaching it", name)
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Report %s is not attached, it exceeds the artifact size limit of %d MB even without simulation.log.", name, config.Config.MaxArtifactSizeMb),
+		})
+		return nil, messages, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to zip report: %w", err)
+	}
+	if trimmed {
+		log.Warn().Msgf("Report %s exceeds the artifact size limit, attaching it without simulation.log", name)
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Report %s is attached without simulation.log, the full report exceeds the artifact size limit of %d MB.", name, config.Config.MaxArtifactSizeMb),
+		})
+	}
+
+	content, err := encodeFile(zippedReport)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []action_kit_api.Artifact{{
+		// Gatling names the report folder "<simulation>-<timestamp>";
+		// including it keeps the artifacts of a run that produced
+		// several reports apart -- they would otherwise all be
+		// attached under the same label.
+		Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_report.zip", name),
+		Data:  content,
+	}}, messages, nil
+}
+
+func summaryArtifact(reportDir string, phases []phaseSummary) ([]action_kit_api.Artifact, []action_kit_api.Message, error) {
+	name := filepath.Base(reportDir)
+	summary, err := readSimulationSummary(reportDir)
+	if err != nil {
+		// Gatling writes no statistics if the simulation was aborted before the
+		// report got generated, that's not a reason to fail the step.
+		log.Warn().Err(err).Msgf("No statistics found for report %s", name)
+		return nil, []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("No summary attached for report %s, Gatling did not generate statistics.", name),
+		}}, nil
+	}
+	summary.Phases = phases
+	content, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return []action_kit_api.Artifact{{
+		Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_summary.json", name),
+		Data:  base64.StdEncoding.EncodeToString(content),
+	}}, nil, nil
+}
+
+func simulationLogArtifact(reportDir string) ([]action_kit_api.Artifact, []action_kit_api.Message, error) {
+	name := filepath.Base(reportDir)
+	simulationLog := filepath.Join(reportDir, "simulation.log")
+	size, err := fileSize(simulationLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	if maxSizeMb := config.Config.MaxArtifactSizeMb; maxSizeMb > 0 && size > maxSizeMb*megabyte {
+		log.Warn().Msgf("simulation.log of report %s exceeds the artifact size limit, not attaching it", name)
+		return nil, []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("simulation.log of report %s is not attached, its %s exceed the artifact size limit of %d MB.", name, formatSize(size), maxSizeMb),
+		}}, nil
+	}
+	content, err := encodeFile(simulationLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []action_kit_api.Artifact{{
+		Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_simulation.log", name),
+		Data:  content,
+	}}, nil, nil
+}
+
+// encodeFile returns the content of file base64 encoded, streaming it through
+// the encoder, so only the encoded artifact is held in memory.
+func encodeFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	var encoded strings.Builder
+	encoded.Grow(base64.StdEncoding.EncodedLen(int(info.Size())))
+	encoder := base64.NewEncoder(base64.StdEncoding, &encoded)
+	if _, err := io.Copy(encoder, f); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return encoded.String(), nil
+}
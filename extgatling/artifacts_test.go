@@ -0,0 +1,26 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_encodeFile(t *testing.T) {
+	content := strings.Repeat("simulation.log line\n", 1000)
+	file := filepath.Join(t.TempDir(), "simulation.log")
+	writeFile(t, file, content)
+
+	encoded, err := encodeFile(file)
+
+	require.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(content)), encoded)
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/exthttp"
+)
+
+// BarrierPath is the path the locations of a synchronized start meet at, as
+// /barrier/<experimentKey>/<executionId>. It is served by the same instance
+// as the aggregation.
+const BarrierPath = "/barrier/"
+
+// barrierLead is how long after the last location arrived the load starts,
+// so all locations learn the start instant in time. The waiting locations
+// poll the barrier on their status calls, so it exceeds their 5s interval.
+const barrierLead = 7 * time.Second
+
+// barrierStatus is what the coordinator knows about a round of a barrier.
+// Times are epoch millis.
+type barrierStatus struct {
+	Round     int              `json:"round"`
+	Locations int              `json:"locations"`
+	Arrivals  map[string]int64 `json:"arrivals"`
+	StartAt   int64            `json:"startAt,omitempty"`
+	Starts    map[string]int64 `json:"starts,omitempty"`
+	// the load weights of the locations, for distributing the load
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// barrierRequest is a location arriving at a barrier, or reporting when it
+// actually started if Started is set.
+type barrierRequest struct {
+	Source    string  `json:"source"`
+	Locations int     `json:"locations"`
+	Round     int     `json:"round,omitempty"`
+	Started   int64   `json:"started,omitempty"`
+	Weight    float64 `json:"weight,omitempty"`
+}
+
+// barrier is the current round of the locations of one experiment execution.
+// A location arriving again at a released round, like with the next
+// synchronized step, opens the next round.
+type barrier struct {
+	status  barrierStatus
+	touched time.Time
+}
+
+var (
+	barriersMu sync.Mutex
+	barriers   = map[string]*barrier{}
+)
+
+func (b *barrier) arrive(source string, locations int, weight float64, now time.Time) {
+	if _, again := b.status.Arrivals[source]; again || b.status.StartAt != 0 && len(b.status.Arrivals) >= b.status.Locations {
+		b.status = barrierStatus{Round: b.status.Round + 1}
+	}
+	if b.status.Arrivals == nil {
+		b.status.Locations = locations
+		b.status.Arrivals = map[string]int64{}
+		b.status.Weights = map[string]float64{}
+	}
+	b.status.Arrivals[source] = now.UnixMilli()
+	b.status.Weights[source] = weight
+	if b.status.StartAt == 0 && len(b.status.Arrivals) >= b.status.Locations {
+		b.status.StartAt = now.Add(barrierLead).UnixMilli()
+	}
+}
+
+func (b *barrier) copyStatus() barrierStatus {
+	status := b.status
+	status.Arrivals = make(map[string]int64, len(b.status.Arrivals))
+	for source, arrived := range b.status.Arrivals {
+		status.Arrivals[source] = arrived
+	}
+	status.Weights = make(map[string]float64, len(b.status.Weights))
+	for source, weight := range b.status.Weights {
+		status.Weights[source] = weight
+	}
+	if b.status.Starts != nil {
+		status.Starts = make(map[string]int64, len(b.status.Starts))
+		for source, started := range b.status.Starts {
+			status.Starts[source] = started
+		}
+	}
+	return status
+}
+
+// ServeBarrier coordinates the synchronized start of the locations: a POST
+// arrives at the barrier or reports the actual start, a GET polls the round.
+func ServeBarrier(w http.ResponseWriter, r *http.Request, body []byte) {
+	if !authorizedForAggregation(w, r) {
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, BarrierPath), "/")
+	if len(segments) != 2 || !aggregationSegment.MatchString(segments[0]) {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := strconv.Atoi(segments[1]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	key := segments[0] + "/" + segments[1]
+	now := time.Now()
+
+	barriersMu.Lock()
+	defer barriersMu.Unlock()
+	for k, b := range barriers {
+		if now.Sub(b.touched) > aggregationRetention {
+			delete(barriers, k)
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		b, found := barriers[key]
+		if !found || strconv.Itoa(b.status.Round) != r.URL.Query().Get("round") {
+			http.NotFound(w, r)
+			return
+		}
+		exthttp.WriteBody(w, b.copyStatus())
+	case http.MethodPost:
+		var request barrierRequest
+		if err := json.Unmarshal(body, &request); err != nil || request.Source == "" {
+			http.Error(w, "invalid barrier request", http.StatusBadRequest)
+			return
+		}
+		b, found := barriers[key]
+		if !found {
+			b = &barrier{}
+			barriers[key] = b
+		}
+		b.touched = now
+		if request.Started != 0 {
+			if b.status.Round != request.Round {
+				http.NotFound(w, r)
+				return
+			}
+			if b.status.Starts == nil {
+				b.status.Starts = map[string]int64{}
+			}
+			b.status.Starts[request.Source] = request.Started
+		} else {
+			if request.Locations <= 0 {
+				http.Error(w, "invalid barrier request", http.StatusBadRequest)
+				return
+			}
+			weight := request.Weight
+			if weight <= 0 {
+				weight = 1
+			}
+			b.arrive(request.Source, request.Locations, weight, now)
+		}
+		exthttp.WriteBody(w, b.copyStatus())
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// barrierClient talks to the coordinator at the configured aggregation.
+type barrierClient struct {
+	url    string
+	client *http.Client
+}
+
+func newBarrierClient(experimentKey string, executionId int) *barrierClient {
+	return &barrierClient{
+		url:    fmt.Sprintf("%s%s%s/%d", strings.TrimSuffix(config.Config.AggregationUrl, "/"), BarrierPath, url.PathEscape(experimentKey), executionId),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *barrierClient) post(request barrierRequest) (barrierStatus, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return barrierStatus{}, err
+	}
+	return c.do(http.MethodPost, c.url, body)
+}
+
+func (c *barrierClient) get(round int) (barrierStatus, error) {
+	return c.do(http.MethodGet, fmt.Sprintf("%s?round=%d", c.url, round), nil)
+}
+
+func (c *barrierClient) do(method, target string, body []byte) (barrierStatus, error) {
+	var status barrierStatus
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return status, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setAggregationToken(req)
+	response, err := c.client.Do(req)
+	if err != nil {
+		return status, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return status, fmt.Errorf("barrier responded with status %d", response.StatusCode)
+	}
+	err = json.NewDecoder(response.Body).Decode(&status)
+	return status, err
+}
+
+// arrive arrives at the barrier of a synchronized start.
+func (c *barrierClient) arrive(start *syncStart) (barrierStatus, error) {
+	return c.post(barrierRequest{Source: start.Source, Locations: start.Locations, Weight: start.Weight})
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_barrier_releases_once_all_locations_arrived(t *testing.T) {
+	now := time.UnixMilli(1767268800000)
+	b := &barrier{}
+
+	b.arrive("location-a", 2, 1, now)
+	assert.Zero(t, b.status.StartAt)
+
+	b.arrive("location-b", 2, 1, now.Add(300*time.Millisecond))
+	assert.Equal(t, now.Add(300*time.Millisecond+barrierLead).UnixMilli(), b.status.StartAt)
+	assert.Equal(t, 0, b.status.Round)
+
+	// the next synchronized step of the same execution
+	b.arrive("location-b", 2, 1, now.Add(time.Minute))
+	assert.Equal(t, 1, b.status.Round)
+	assert.Zero(t, b.status.StartAt)
+	assert.Equal(t, map[string]int64{"location-b": now.Add(time.Minute).UnixMilli()}, b.status.Arrivals)
+}
+
+func Test_pollSyncStart_releases_once_all_locations_arrived(t *testing.T) {
+	resetBarriers(t)
+	useBarrierServer(t)
+	now := time.Now()
+	states := make([]*GatlingLoadTestRunState, 2)
+	for i, source := range []string{"location-a", "location-b"} {
+		states[i] = &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42,
+			Sync: &syncStart{Locations: 2, Timeout: 5000, Source: source, Weight: float64(i + 1)}}
+	}
+
+	messages, err := arriveAtSyncStart(states[0], now)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "⏳ Waiting for 1 of 2 locations to start synchronized", messages[0].Message)
+	_, released, err := pollSyncStart(states[0], now)
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	_, err = arriveAtSyncStart(states[1], now)
+	require.NoError(t, err)
+	status, released, err := pollSyncStart(states[0], now)
+	require.NoError(t, err)
+	require.True(t, released)
+	assert.NotZero(t, states[0].Sync.StartAt)
+	assert.Zero(t, states[0].Sync.WaitingSince)
+	assert.Len(t, status.Arrivals, 2)
+	assert.Equal(t, map[string]float64{"location-a": 1, "location-b": 2}, status.Weights)
+	_, released, err = pollSyncStart(states[1], now)
+	require.NoError(t, err)
+	require.True(t, released)
+	assert.Equal(t, states[0].Sync.StartAt, states[1].Sync.StartAt)
+}
+
+func Test_pollSyncStart_times_out(t *testing.T) {
+	resetBarriers(t)
+	useBarrierServer(t)
+	now := time.Now()
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42,
+		Sync: &syncStart{Locations: 3, Timeout: 200, Source: "location-a"}}
+	_, err := arriveAtSyncStart(state, now)
+	require.NoError(t, err)
+
+	_, _, err = pollSyncStart(state, now.Add(100*time.Millisecond))
+	require.NoError(t, err)
+	_, _, err = pollSyncStart(state, now.Add(time.Second))
+	assert.EqualError(t, err, "only 1 of 3 locations reached the synchronized start within 200ms")
+}
+
+func Test_ServeBarrier_requires_the_token(t *testing.T) {
+	resetBarriers(t)
+	useAggregationToken(t)
+	recorder := httptest.NewRecorder()
+
+	ServeBarrier(recorder, httptest.NewRequest(http.MethodGet, "/barrier/ADM-1/42?round=0", nil), nil)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_skewMessages(t *testing.T) {
+	status := barrierStatus{Locations: 2, StartAt: 1000, Starts: map[string]int64{"location-b": 1012}}
+	assert.Empty(t, skewMessages(status))
+
+	status.Starts["location-a"] = 1003
+	messages := skewMessages(status)
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, "⏱️ Start skew between locations: 9 ms", messages[0].Message)
+	assert.Equal(t, "| Location | Started |\n|---|---:|\n| location-a | +3 ms |\n| location-b | +12 ms |\n", messages[1].Message)
+}
+
+func Test_syncCommands(t *testing.T) {
+	compile, run := syncCommands([]string{"mvn", "integration-test", "-o", "-Pkotlin"})
+
+	assert.Equal(t, []string{"mvn", "test-compile", "-o", "-Pkotlin"}, compile)
+	assert.Equal(t, []string{"mvn", "gatling:test", "-o", "-Pkotlin"}, run)
+}
+
+func Test_parseSyncStart(t *testing.T) {
+	start, err := parseSyncStart(GatlingLoadTestRunConfig{SyncLocations: new(1)})
+	require.NoError(t, err)
+	assert.Nil(t, start)
+
+	_, err = parseSyncStart(GatlingLoadTestRunConfig{SyncLocations: new(2)})
+	assert.ErrorContains(t, err, "STEADYBIT_EXTENSION_AGGREGATION_URL")
+
+	config.Config.AggregationUrl = "http://aggregation:8087"
+	defer func() { config.Config.AggregationUrl = "" }()
+	start, err = parseSyncStart(GatlingLoadTestRunConfig{SyncLocations: new(3), SyncTimeout: new(30000)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, start.Locations)
+	assert.Equal(t, int64(30000), start.Timeout)
+}
+
+func useBarrierServer(t *testing.T) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ServeBarrier(w, r, body)
+	}))
+	t.Cleanup(server.Close)
+	useAggregationToken(t)
+	config.Config.AggregationUrl = server.URL
+	t.Cleanup(func() { config.Config.AggregationUrl = "" })
+}
+
+func resetBarriers(t *testing.T) {
+	t.Helper()
+	barriersMu.Lock()
+	barriers = map[string]*barrier{}
+	barriersMu.Unlock()
+}
+
+func Test_shareOf_distributes_by_weight(t *testing.T) {
+	status := barrierStatus{
+		Arrivals: map[string]int64{"location-c": 1, "location-a": 2, "location-b": 3},
+		Weights:  map[string]float64{"location-c": 2, "location-a": 1, "location-b": 1},
+	}
+
+	share := shareOf(status, "location-c")
+
+	assert.Equal(t, locationShare{Index: 2, Count: 3, Weight: 2, Share: 0.5}, share)
+	assert.Equal(t, []string{
+		"-Dsteadybit.location.index=2",
+		"-Dsteadybit.location.count=3",
+		"-Dsteadybit.location.weight=2",
+		"-Dsteadybit.location.share=0.5",
+	}, share.properties())
+	assert.Equal(t, "⚖️ This location runs 50% of the load, as location 3 of 3", shareMessage(share).Message)
+}
+
+func Test_parseSyncStart_requires_a_synchronized_start_to_distribute(t *testing.T) {
+	_, err := parseSyncStart(GatlingLoadTestRunConfig{LoadDistribution: loadDistributionDistribute})
+
+	assert.ErrorContains(t, err, "requires a synchronized start")
+}
+
+func Test_runStartOf_takes_the_earliest_run_header(t *testing.T) {
+	reportFolder := t.TempDir()
+	assert.Zero(t, runStartOf(reportFolder))
+
+	writeFile(t, filepath.Join(reportFolder, "second-2", "simulation.log"), string(newSimulationLogWriter("Second", 1767268800500).bytes()))
+	writeFile(t, filepath.Join(reportFolder, "first-1", "simulation.log"), string(newSimulationLogWriter("First", 1767268800100).bytes()))
+
+	assert.Equal(t, int64(1767268800100), runStartOf(reportFolder))
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// baselineNamePattern limits baseline names to what is safe as a file name.
+var baselineNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// baselineOptions are what a local run does with baselines: save its
+// statistics under a name, compare them to a named baseline, and fail if they
+// degraded too much. Unset thresholds are nil.
+type baselineOptions struct {
+	Save                       string   `json:"save,omitempty"`
+	Compare                    string   `json:"compare,omitempty"`
+	MaxP95Increase             *float64 `json:"maxP95Increase,omitempty"`             // percent
+	MaxErrorPercentageIncrease *float64 `json:"maxErrorPercentageIncrease,omitempty"` // percentage points
+	MaxThroughputDecrease      *float64 `json:"maxThroughputDecrease,omitempty"`      // percent
+}
+
+// baseline are the statistics of a run stored under a name.
+type baseline struct {
+	Name                  string               `json:"name"`
+	ExperimentKey         string               `json:"experimentKey"`
+	ExperimentExecutionId int                  `json:"experimentExecutionId"`
+	Created               time.Time            `json:"created"`
+	Simulations           []*simulationSummary `json:"simulations"`
+}
+
+// parseBaselineOptions builds the baseline options from the parameters of the
+// action, nil if neither a baseline to save nor one to compare to is set.
+func parseBaselineOptions(config GatlingLoadTestRunConfig) (*baselineOptions, error) {
+	options := &baselineOptions{
+		Save:                       strings.TrimSpace(config.BaselineSave),
+		Compare:                    strings.TrimSpace(config.BaselineCompare),
+		MaxP95Increase:             config.BaselineMaxP95Increase,
+		MaxErrorPercentageIncrease: config.BaselineMaxErrorPercentageIncrease,
+		MaxThroughputDecrease:      config.BaselineMaxThroughputDecrease,
+	}
+	for _, name := range []string{options.Save, options.Compare} {
+		if name != "" && !baselineNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid baseline name %q, use letters, digits, '.', '_' and '-' only", name)
+		}
+	}
+	hasThresholds := options.MaxP95Increase != nil || options.MaxErrorPercentageIncrease != nil || options.MaxThroughputDecrease != nil
+	if options.Compare == "" && hasThresholds {
+		return nil, fmt.Errorf("the maximum degradation needs a baseline to compare to")
+	}
+	if options.Save == "" && options.Compare == "" {
+		return nil, nil
+	}
+	return options, nil
+}
+
+func baselinePath(name string) string {
+	return filepath.Join(config.Config.BaselineDir, name+".json")
+}
+
+// saveBaseline stores the statistics under the name, replacing the baseline
+// stored under it before.
+func saveBaseline(b *baseline) error {
+	if err := os.MkdirAll(config.Config.BaselineDir, 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first, a run comparing to the baseline
+	// meanwhile must not read half of it
+	tmp, err := os.CreateTemp(config.Config.BaselineDir, ".baseline-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), baselinePath(b.Name))
+}
+
+// loadBaseline reads the baseline stored under the name. The error satisfies
+// os.IsNotExist if there is none.
+func loadBaseline(name string) (*baseline, error) {
+	content, err := os.ReadFile(baselinePath(name))
+	if err != nil {
+		return nil, err
+	}
+	var b baseline
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// baselineDelta pairs the statistics of a request with the ones of the
+// baseline, which are nil if the request isn't in the baseline.
+type baselineDelta struct {
+	// the name of the request in the table, and how messages refer to it
+	Request  string
+	Subject  string
+	Current  requestSummary
+	Baseline *requestSummary
+}
+
+// compareToBaseline pairs the requests of the run's simulations with the ones
+// of the same simulation in the baseline, all requests first.
+func compareToBaseline(b *baseline, summaries []*simulationSummary) []baselineDelta {
+	var deltas []baselineDelta
+	for _, summary := range summaries {
+		prefix := ""
+		if len(summaries) > 1 {
+			prefix = summary.Simulation + ": "
+		}
+		var base *simulationSummary
+		for _, candidate := range b.Simulations {
+			if candidate.Simulation == summary.Simulation {
+				base = candidate
+				break
+			}
+		}
+		var baseGlobal *requestSummary
+		baseRequests := map[string]*requestSummary{}
+		if base != nil {
+			baseGlobal = &base.Global
+			for i := range base.Requests {
+				baseRequests[base.Requests[i].Name] = &base.Requests[i]
+			}
+		}
+		deltas = append(deltas, baselineDelta{
+			Request:  prefix + "All requests",
+			Subject:  prefix + "all requests",
+			Current:  summary.Global,
+			Baseline: baseGlobal,
+		})
+		for _, request := range summary.Requests {
+			deltas = append(deltas, baselineDelta{
+				Request:  prefix + request.Name,
+				Subject:  fmt.Sprintf("%srequest %q", prefix, request.Name),
+				Current:  request,
+				Baseline: baseRequests[request.Name],
+			})
+		}
+	}
+	return deltas
+}
+
+// relativeChange returns the change from base to current in percent, NaN if
+// base is zero.
+func relativeChange(base, current float64) float64 {
+	if base == 0 {
+		return math.NaN()
+	}
+	return (current - base) * 100 / base
+}
+
+// baselineTable renders the deltas as a markdown table.
+func baselineTable(deltas []baselineDelta) string {
+	var table strings.Builder
+	table.WriteString("| Request | p95 | Δ p95 | Errors | Δ Errors | Throughput | Δ Throughput |\n")
+	table.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, delta := range deltas {
+		current := delta.Current
+		p95Delta, errorsDelta, throughputDelta := "new", "new", "new"
+		if base := delta.Baseline; base != nil {
+			p95Delta = formatChange(relativeChange(base.P95, current.P95), "%")
+			errorsDelta = formatChange(current.ErrorPercentage-base.ErrorPercentage, " pts")
+			throughputDelta = formatChange(relativeChange(base.Throughput, current.Throughput), "%")
+		}
+		_, _ = fmt.Fprintf(&table, "| %s | %s ms | %s | %s%% | %s | %s req/s | %s |\n",
+			markdownCell(delta.Request), formatNumber(current.P95), p95Delta,
+			formatNumber(current.ErrorPercentage), errorsDelta, formatNumber(current.Throughput), throughputDelta)
+	}
+	return table.String()
+}
+
+func formatChange(change float64, unit string) string {
+	if math.IsNaN(change) {
+		return "-"
+	}
+	sign := ""
+	if change >= 0 {
+		sign = "+"
+	}
+	return sign + formatNumber(change) + unit
+}
+
+// baselineDegradations returns the requests that degraded more than allowed.
+func baselineDegradations(options *baselineOptions, deltas []baselineDelta) []string {
+	var degradations []string
+	for _, delta := range deltas {
+		base, current := delta.Baseline, delta.Current
+		if base == nil {
+			continue
+		}
+		if options.MaxP95Increase != nil {
+			if change := relativeChange(base.P95, current.P95); !math.IsNaN(change) && change > *options.MaxP95Increase {
+				degradations = append(degradations, fmt.Sprintf("p95 response time of %s increased by %s%% from %s ms to %s ms, more than %s%%",
+					delta.Subject, formatNumber(change), formatNumber(base.P95), formatNumber(current.P95), formatNumber(*options.MaxP95Increase)))
+			}
+		}
+		if options.MaxErrorPercentageIncrease != nil {
+			if change := current.ErrorPercentage - base.ErrorPercentage; change > *options.MaxErrorPercentageIncrease {
+				degradations = append(degradations, fmt.Sprintf("error percentage of %s increased by %s points from %s%% to %s%%, more than %s",
+					delta.Subject, formatNumber(change), formatNumber(base.ErrorPercentage), formatNumber(current.ErrorPercentage), formatNumber(*options.MaxErrorPercentageIncrease)))
+			}
+		}
+		if options.MaxThroughputDecrease != nil {
+			if change := relativeChange(base.Throughput, current.Throughput); !math.IsNaN(change) && -change > *options.MaxThroughputDecrease {
+				degradations = append(degradations, fmt.Sprintf("throughput of %s decreased by %s%% from %s req/s to %s req/s, more than %s%%",
+					delta.Subject, formatNumber(-change), formatNumber(base.Throughput), formatNumber(current.Throughput), formatNumber(*options.MaxThroughputDecrease)))
+			}
+		}
+	}
+	return degradations
+}
+
+// checkBaseline compares the statistics of a run to the baseline and saves them
+// as baseline, as configured. Returns the messages with the comparison and an
+// error if the run degraded more than allowed. A missing baseline is reported,
+// but doesn't fail the run, the first run of a series has nothing to compare
+// to.
+func checkBaseline(state *GatlingLoadTestRunState, summaries []*simulationSummary) ([]action_kit_api.Message, *action_kit_api.ActionKitError) {
+	options := state.Baseline
+	if len(summaries) == 0 {
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: "Baseline neither compared nor saved, Gatling didn't generate a report.",
+		}}, nil
+	}
+
+	var messages []action_kit_api.Message
+	var resultErr *action_kit_api.ActionKitError
+	if options.Compare != "" {
+		b, err := loadBaseline(options.Compare)
+		if os.IsNotExist(err) {
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("Baseline %q not found, nothing to compare to.", options.Compare),
+			})
+		} else if err != nil {
+			log.Warn().Err(err).Msgf("Failed to read baseline %s", options.Compare)
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("Failed to read baseline %q: %s", options.Compare, err),
+			})
+		} else {
+			deltas := compareToBaseline(b, summaries)
+			messages = append(messages,
+				markdownMessage(fmt.Sprintf("### Compared to Baseline %q of %s, Execution %d", b.Name, b.ExperimentKey, b.ExperimentExecutionId)),
+				markdownMessage(baselineTable(deltas)))
+			degradations := baselineDegradations(options, deltas)
+			for _, degradation := range degradations {
+				messages = append(messages, action_kit_api.Message{
+					Level:   extutil.Ptr(action_kit_api.Error),
+					Message: "Degraded: " + degradation,
+				})
+			}
+			if len(degradations) > 0 {
+				resultErr = &action_kit_api.ActionKitError{
+					Status: extutil.Ptr(action_kit_api.Failed),
+					Title:  fmt.Sprintf("Gatling run degraded compared to baseline %q.", b.Name),
+				}
+			}
+		}
+	}
+
+	if options.Save != "" {
+		err := saveBaseline(&baseline{
+			Name:                  options.Save,
+			ExperimentKey:         state.ExperimentKey,
+			ExperimentExecutionId: state.ExperimentExecutionId,
+			Created:               time.Now(),
+			Simulations:           summaries,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to save baseline %s", options.Save)
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("Failed to save baseline %q: %s", options.Save, err),
+			})
+		} else {
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Saved the statistics as baseline %q.", options.Save),
+			})
+		}
+	}
+	return messages, resultErr
+}
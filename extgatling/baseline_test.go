@@ -0,0 +1,98 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseBaselineOptions(t *testing.T) {
+	options, err := parseBaselineOptions(GatlingLoadTestRunConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, options)
+
+	options, err = parseBaselineOptions(GatlingLoadTestRunConfig{BaselineSave: " steady-state ", BaselineCompare: "v1.2", BaselineMaxP95Increase: new(20.0)})
+	require.NoError(t, err)
+	assert.Equal(t, &baselineOptions{Save: "steady-state", Compare: "v1.2", MaxP95Increase: new(20.0)}, options)
+
+	_, err = parseBaselineOptions(GatlingLoadTestRunConfig{BaselineSave: "../etc/passwd"})
+	assert.ErrorContains(t, err, "invalid baseline name")
+
+	_, err = parseBaselineOptions(GatlingLoadTestRunConfig{BaselineSave: "steady-state", BaselineMaxThroughputDecrease: new(10.0)})
+	assert.ErrorContains(t, err, "needs a baseline to compare to")
+}
+
+func Test_baselineTable_and_degradations(t *testing.T) {
+	base := &baseline{Simulations: []*simulationSummary{{
+		Simulation: "basicsimulation",
+		Global:     requestSummary{P95: 200, ErrorPercentage: 1, Throughput: 20},
+		Requests:   []requestSummary{{Name: "home", P95: 50, Throughput: 10}},
+	}}}
+	current := []*simulationSummary{{
+		Simulation: "basicsimulation",
+		Global:     requestSummary{P95: 300, ErrorPercentage: 5, Throughput: 15},
+		Requests: []requestSummary{
+			{Name: "checkout | pay", P95: 500, Throughput: 5},
+			{Name: "home", P95: 55, Throughput: 10},
+		},
+	}}
+
+	deltas := compareToBaseline(base, current)
+
+	assert.Equal(t, "| Request | p95 | Δ p95 | Errors | Δ Errors | Throughput | Δ Throughput |\n"+
+		"|---|---:|---:|---:|---:|---:|---:|\n"+
+		"| All requests | 300 ms | +50% | 5% | +4 pts | 15 req/s | -25% |\n"+
+		"| checkout \\| pay | 500 ms | new | 0% | new | 5 req/s | new |\n"+
+		"| home | 55 ms | +10% | 0% | +0 pts | 10 req/s | +0% |\n", baselineTable(deltas))
+
+	degradations := baselineDegradations(&baselineOptions{
+		MaxP95Increase:             new(20.0),
+		MaxErrorPercentageIncrease: new(5.0),
+		MaxThroughputDecrease:      new(10.0),
+	}, deltas)
+
+	assert.Equal(t, []string{
+		"p95 response time of all requests increased by 50% from 200 ms to 300 ms, more than 20%",
+		"throughput of all requests decreased by 25% from 20 req/s to 15 req/s, more than 10%",
+	}, degradations)
+}
+
+func Test_checkBaseline_saves_and_compares(t *testing.T) {
+	config.Config.BaselineDir = filepath.Join(t.TempDir(), "baselines")
+	defer func() { config.Config.BaselineDir = "" }()
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+	summaries := readSimulationSummaries([]string{reportDir})
+
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42, Baseline: &baselineOptions{Save: "steady-state", Compare: "steady-state"}}
+	messages, err := checkBaseline(state, summaries)
+
+	assert.Nil(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, `Baseline "steady-state" not found, nothing to compare to.`, messages[0].Message)
+	assert.Equal(t, `Saved the statistics as baseline "steady-state".`, messages[1].Message)
+	_, statErr := os.Stat(filepath.Join(config.Config.BaselineDir, "steady-state.json"))
+	require.NoError(t, statErr)
+
+	summaries[0].Global.P95 = 600
+	state = &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 43, Baseline: &baselineOptions{Compare: "steady-state", MaxP95Increase: new(50.0)}}
+	messages, err = checkBaseline(state, summaries)
+
+	require.NotNil(t, err)
+	assert.Equal(t, action_kit_api.Failed, *err.Status)
+	assert.Equal(t, `Gatling run degraded compared to baseline "steady-state".`, err.Title)
+	require.Len(t, messages, 3)
+	assert.Equal(t, `### Compared to Baseline "steady-state" of ADM-1, Execution 42`, messages[0].Message)
+	assert.Equal(t, markdownMessageType, *messages[1].Type)
+	assert.Contains(t, messages[1].Message, "| All requests | 600 ms | +100% |")
+	assert.Equal(t, "Degraded: p95 response time of all requests increased by 100% from 300 ms to 600 ms, more than 50%", messages[2].Message)
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// breakdownRows is how many requests and KO reasons the tables show.
+const breakdownRows = 10
+
+// koMessageLimit bounds the distinct KO messages kept per report, KO messages
+// of further kinds are counted as otherKoMessages.
+const (
+	koMessageLimit  = 100
+	koMessageLength = 200
+	otherKoMessages = "(other messages)"
+)
+
+// koMessageIds are the parts of KO messages that differ from request to
+// request, like UUIDs, long hex strings and numbers of five or more digits.
+var koMessageIds = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b|\b[0-9a-fA-F]{16,}\b|\d{5,}`)
+
+// requestBreakdown are the statistics of one request, by name including its
+// group path. Response times are in milliseconds.
+type requestBreakdown struct {
+	Name     string
+	Requests int64
+	Ko       int64
+	P95      float64
+	P99      float64
+}
+
+// koBreakdown is how often requests failed with a message.
+type koBreakdown struct {
+	Message  string
+	Count    int64
+	Requests []string // names of the requests that failed with it, sorted
+}
+
+// breakdownAggregator aggregates the requests of a simulation.log by request
+// and by KO message.
+type breakdownAggregator struct {
+	responseTimes   map[string]*responseTimeHistogram
+	kos             map[string]int64
+	messages        map[string]*koBreakdown
+	messageRequests map[string]map[string]struct{}
+	totalKo         int64
+}
+
+func newBreakdownAggregator() *breakdownAggregator {
+	return &breakdownAggregator{
+		responseTimes:   map[string]*responseTimeHistogram{},
+		kos:             map[string]int64{},
+		messages:        map[string]*koBreakdown{},
+		messageRequests: map[string]map[string]struct{}{},
+	}
+}
+
+func (a *breakdownAggregator) visit(record any) {
+	request, ok := record.(*requestRecord)
+	if !ok {
+		return
+	}
+	name := requestPath(request)
+	histogram, found := a.responseTimes[name]
+	if !found {
+		histogram = &responseTimeHistogram{}
+		a.responseTimes[name] = histogram
+	}
+	histogram.add(request.End - request.Start)
+	if request.Ok {
+		return
+	}
+	a.totalKo++
+	a.kos[name]++
+	message := normalizeKoMessage(request.Message)
+	ko, found := a.messages[message]
+	if !found && len(a.messages) >= koMessageLimit {
+		message = otherKoMessages
+		ko, found = a.messages[message]
+	}
+	if !found {
+		ko = &koBreakdown{Message: message}
+		a.messages[message] = ko
+		a.messageRequests[message] = map[string]struct{}{}
+	}
+	ko.Count++
+	a.messageRequests[message][name] = struct{}{}
+}
+
+// result returns the requests ordered by KO count, then p95, the KO messages
+// by count and the total KO count.
+func (a *breakdownAggregator) result() ([]requestBreakdown, []koBreakdown, int64) {
+	requests := make([]requestBreakdown, 0, len(a.responseTimes))
+	for name, histogram := range a.responseTimes {
+		requests = append(requests, requestBreakdown{
+			Name:     name,
+			Requests: histogram.total,
+			Ko:       a.kos[name],
+			P95:      histogram.percentile(95),
+			P99:      histogram.percentile(99),
+		})
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Ko != requests[j].Ko {
+			return requests[i].Ko > requests[j].Ko
+		}
+		if requests[i].P95 != requests[j].P95 {
+			return requests[i].P95 > requests[j].P95
+		}
+		return requests[i].Name < requests[j].Name
+	})
+
+	reasons := make([]koBreakdown, 0, len(a.messages))
+	for message, ko := range a.messages {
+		reason := *ko
+		reason.Requests = nil
+		for name := range a.messageRequests[message] {
+			reason.Requests = append(reason.Requests, name)
+		}
+		sort.Strings(reason.Requests)
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Message < reasons[j].Message
+	})
+	return requests, reasons, a.totalKo
+}
+
+// normalizeKoMessage replaces the ids in a KO message and shortens it, so the
+// messages of requests failing the same way are counted together.
+func normalizeKoMessage(message string) string {
+	message = koMessageIds.ReplaceAllString(strings.TrimSpace(message), "…")
+	if message == "" {
+		return "(no message)"
+	}
+	if runes := []rune(message); len(runes) > koMessageLength {
+		message = string(runes[:koMessageLength]) + "…"
+	}
+	return message
+}
+
+// requestPath names a request by its group path, like "checkout / pay".
+func requestPath(request *requestRecord) string {
+	return strings.Join(append(append([]string{}, request.Groups...), request.Name), " / ")
+}
+
+// requestTable renders the top requests as a markdown table.
+func requestTable(requests []requestBreakdown) string {
+	var table strings.Builder
+	table.WriteString("| Request | Requests | KO | Errors | p95 | p99 |\n")
+	table.WriteString("|---|---:|---:|---:|---:|---:|\n")
+	for _, request := range requests[:min(len(requests), breakdownRows)] {
+		_, _ = fmt.Fprintf(&table, "| %s | %d | %d | %s%% | %s ms | %s ms |\n",
+			markdownCell(request.Name), request.Requests, request.Ko, formatNumber(float64(request.Ko)*100/float64(request.Requests)),
+			formatNumber(request.P95), formatNumber(request.P99))
+	}
+	return table.String()
+}
+
+// koTable renders the top KO messages as a markdown table.
+func koTable(reasons []koBreakdown, totalKo int64) string {
+	var table strings.Builder
+	table.WriteString("| KO Reason | Count | Share | Requests |\n")
+	table.WriteString("|---|---:|---:|---|\n")
+	for _, reason := range reasons[:min(len(reasons), breakdownRows)] {
+		requests := reason.Requests
+		if len(requests) > 3 {
+			requests = append(requests[:3:3], fmt.Sprintf("%d more", len(reason.Requests)-3))
+		}
+		_, _ = fmt.Fprintf(&table, "| %s | %d | %s%% | %s |\n",
+			markdownCell(reason.Message), reason.Count, formatNumber(float64(reason.Count)*100/float64(totalKo)), markdownCell(strings.Join(requests, ", ")))
+	}
+	return table.String()
+}
+
+// markdownCell escapes text to not break the table it is shown in.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// breakdownMessages returns the tables of the top requests and KO reasons of
+// each analyzed report.
+func breakdownMessages(reportDirs []string, analyses map[string]*reportAnalysis) []action_kit_api.Message {
+	var messages []action_kit_api.Message
+	for _, reportDir := range reportDirs {
+		name := filepath.Base(reportDir)
+		analysis := analyses[reportDir]
+		if analysis == nil || analysis.err != nil {
+			continue
+		}
+		requests, reasons, totalKo := analysis.breakdown.result()
+		if len(requests) == 0 {
+			continue
+		}
+		suffix := ""
+		if len(reportDirs) > 1 {
+			suffix = " of " + name
+		}
+		messages = append(messages, markdownMessage("### Top Requests"+suffix), markdownMessage(requestTable(requests)))
+		if len(reasons) == 0 {
+			continue
+		}
+		messages = append(messages, markdownMessage("### Top KO Reasons"+suffix), markdownMessage(koTable(reasons, totalKo)))
+	}
+	return messages
+}
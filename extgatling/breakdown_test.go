@@ -0,0 +1,113 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_breakdownAggregator(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start, start+100, true, "").
+		request(nil, "home", start, start+300, true, "").
+		request([]string{"checkout"}, "pay", start, start+50, false, "status.find.is(200), but actually found 500").
+		request([]string{"checkout"}, "pay", start, start+60, false, "status.find.is(200), but actually found 500").
+		request([]string{"checkout"}, "cart", start, start+900, false, "i.o.TimeoutException | read timed out").
+		request([]string{"checkout"}, "cart", start, start+20, true, "").
+		bytes()
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), content, 0644))
+
+	aggregator := newBreakdownAggregator()
+	require.NoError(t, readSimulationLog(filepath.Join(reportDir, "simulation.log"), aggregator.visit))
+	requests, reasons, totalKo := aggregator.result()
+
+	assert.Equal(t, []requestBreakdown{
+		{Name: "checkout / pay", Requests: 2, Ko: 2, P95: 60, P99: 60},
+		{Name: "checkout / cart", Requests: 2, Ko: 1, P95: 900, P99: 900},
+		{Name: "home", Requests: 2, P95: 300, P99: 300},
+	}, requests)
+	assert.Equal(t, []koBreakdown{
+		{Message: "status.find.is(200), but actually found 500", Count: 2, Requests: []string{"checkout / pay"}},
+		{Message: "i.o.TimeoutException | read timed out", Count: 1, Requests: []string{"checkout / cart"}},
+	}, reasons)
+	assert.Equal(t, int64(3), totalKo)
+
+	assert.Equal(t, "| Request | Requests | KO | Errors | p95 | p99 |\n"+
+		"|---|---:|---:|---:|---:|---:|\n"+
+		"| checkout / pay | 2 | 2 | 100% | 60 ms | 60 ms |\n"+
+		"| checkout / cart | 2 | 1 | 50% | 900 ms | 900 ms |\n"+
+		"| home | 2 | 0 | 0% | 300 ms | 300 ms |\n", requestTable(requests))
+	assert.Equal(t, "| KO Reason | Count | Share | Requests |\n"+
+		"|---|---:|---:|---|\n"+
+		"| status.find.is(200), but actually found 500 | 2 | 66.67% | checkout / pay |\n"+
+		"| i.o.TimeoutException \\| read timed out | 1 | 33.33% | checkout / cart |\n", koTable(reasons, totalKo))
+}
+
+func Test_breakdownAggregator_bounds_the_KO_messages(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	writer := newSimulationLogWriter("BasicSimulation", start, "Users")
+	for i := range koMessageLimit + 50 {
+		writer.request(nil, "order", start, start+10, false, fmt.Sprintf("order %d of kind %c failed", 100000+i, 'A'+i%26))
+		writer.request(nil, "order", start, start+10, false, fmt.Sprintf("kind %d is unknown", i))
+	}
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), writer.bytes(), 0644))
+
+	aggregator := newBreakdownAggregator()
+	require.NoError(t, readSimulationLog(filepath.Join(reportDir, "simulation.log"), aggregator.visit))
+	_, reasons, totalKo := aggregator.result()
+
+	assert.Equal(t, int64(2*(koMessageLimit+50)), totalKo)
+	assert.Len(t, reasons, koMessageLimit+1)
+	assert.Contains(t, reasons, koBreakdown{Message: "order … of kind A failed", Count: 6, Requests: []string{"order"}})
+	var other int64
+	for _, reason := range reasons {
+		if reason.Message == otherKoMessages {
+			other = reason.Count
+		}
+	}
+	assert.Equal(t, int64(koMessageLimit+50-(koMessageLimit-26)), other)
+}
+
+func Test_normalizeKoMessage(t *testing.T) {
+	assert.Equal(t, "status.find.is(200), but actually found 500", normalizeKoMessage(" status.find.is(200), but actually found 500 "))
+	assert.Equal(t, "order … not found", normalizeKoMessage("order 0b4f3c9e-7a4e-4a4b-9d1e-2f6b8a3c1d2e not found"))
+	assert.Equal(t, "session … expired at …", normalizeKoMessage("session 9f86d081884c7d659a2feaa0c55ad015 expired at 1767268800000"))
+	assert.Equal(t, "(no message)", normalizeKoMessage(""))
+	assert.Len(t, []rune(normalizeKoMessage(strings.Repeat("x", 500))), koMessageLength+1)
+}
+
+func Test_koTable_limits_the_requests(t *testing.T) {
+	table := koTable([]koBreakdown{{Message: "boom", Count: 5, Requests: []string{"a", "b", "c", "d", "e"}}}, 5)
+
+	assert.Contains(t, table, "| boom | 5 | 100% | a, b, c, 2 more |")
+}
+
+func Test_breakdownMessages(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start, start+100, true, "").
+		bytes()
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), content, 0644))
+
+	messages := breakdownMessages([]string{reportDir}, analyzeReports([]string{reportDir}, nil, false))
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, "### Top Requests", messages[0].Message)
+	assert.Contains(t, messages[1].Message, "| home | 1 | 0 | 0% | 100 ms | 100 ms |")
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// startInCgroup makes cmd start right in group, so neither Maven nor the JVM
+// it forks ever run outside the limits. The returned function closes the
+// group once cmd started.
+func startInCgroup(cmd *exec.Cmd, group string) (func(), error) {
+	dir, err := os.Open(group)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { _ = dir.Close() }, nil
+}
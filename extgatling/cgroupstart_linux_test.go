@@ -0,0 +1,30 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_startInCgroup(t *testing.T) {
+	cmd := exec.Command("true")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	closeCgroup, err := startInCgroup(cmd, t.TempDir())
+	require.NoError(t, err)
+	defer closeCgroup()
+
+	assert.True(t, cmd.SysProcAttr.Setpgid)
+	assert.True(t, cmd.SysProcAttr.UseCgroupFD)
+	assert.Positive(t, cmd.SysProcAttr.CgroupFD)
+
+	_, err = startInCgroup(exec.Command("true"), "/nonexistent")
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// startInCgroup is only supported on Linux, which has cgroups.
+func startInCgroup(_ *exec.Cmd, _ string) (func(), error) {
+	return nil, errors.New("cgroups require Linux")
+}
@@ -0,0 +1,501 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/exttracing"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extconversion"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	checkActionId   = "com.steadybit.extension_gatling.check"
+	checkMetricName = "gatling_check"
+
+	checkMetricErrorPercentage = "errorPercentage"
+	checkMetricP50             = "p50"
+	checkMetricP95             = "p95"
+	checkMetricP99             = "p99"
+	checkMetricThroughput      = "throughput"
+
+	checkComparisonAtMost  = "atMost"
+	checkComparisonAtLeast = "atLeast"
+
+	checkModeAllTheTime  = "allTheTime"
+	checkModeAtLeastOnce = "atLeastOnce"
+)
+
+// GatlingCheckAction checks the live statistics of the local runs of the same
+// experiment execution, which have to run on this extension instance.
+type GatlingCheckAction struct{}
+
+type GatlingCheckState struct {
+	ExecutionId           uuid.UUID      `json:"executionId"`
+	ExperimentKey         string         `json:"experimentKey"`
+	ExperimentExecutionId int            `json:"experimentExecutionId"`
+	Duration              int64          `json:"duration"` // milliseconds
+	End                   int64          `json:"end"`      // epoch millis
+	Window                int64          `json:"window"`   // milliseconds
+	Simulation            string         `json:"simulation,omitempty"`
+	Condition             checkCondition `json:"condition"`
+	Mode                  string         `json:"mode"`
+	// the action execution ids of the runs the check is attached to
+	Runs []uuid.UUID `json:"runs"`
+	// whether any simulation had requests to check while the check ran
+	Evaluated bool `json:"evaluated"`
+	// whether the condition was met once, for the at least once mode
+	Fulfilled bool `json:"fulfilled"`
+}
+
+// checkCondition compares a live metric to a threshold.
+type checkCondition struct {
+	Metric     string  `json:"metric"`
+	Comparison string  `json:"comparison"`
+	Threshold  float64 `json:"threshold"` // milliseconds for response times
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[GatlingCheckState]           = (*GatlingCheckAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[GatlingCheckState] = (*GatlingCheckAction)(nil)
+)
+
+func NewGatlingCheckAction() action_kit_sdk.Action[GatlingCheckState] {
+	return &GatlingCheckAction{}
+}
+
+func (c *GatlingCheckAction) NewEmptyState() GatlingCheckState {
+	return GatlingCheckState{}
+}
+
+func (c *GatlingCheckAction) Describe() action_kit_api.ActionDescription {
+	description := action_kit_api.ActionDescription{
+		Id:          checkActionId,
+		Label:       "Gatling Metrics Check",
+		Description: "Check the live metrics of a Gatling load test running in the same experiment.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(actionIcon),
+		Technology:  new("Gatling"),
+		Kind:        action_kit_api.Check,
+		TimeControl: action_kit_api.TimeControlInternal,
+		Hint: &action_kit_api.ActionHint{
+			Content: "The check attaches to the Gatling load tests of the experiment that are run by the same gatling extension, start it after the load test.",
+			Type:    action_kit_api.HintInfo,
+		},
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long the metrics are checked."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+			},
+			{
+				Name:         "metric",
+				Label:        "Metric",
+				Description:  new("The metric to check, computed over the evaluation window."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(checkMetricErrorPercentage),
+				Required:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Error percentage", Value: checkMetricErrorPercentage},
+					action_kit_api.ExplicitParameterOption{Label: "p50 response time", Value: checkMetricP50},
+					action_kit_api.ExplicitParameterOption{Label: "p95 response time", Value: checkMetricP95},
+					action_kit_api.ExplicitParameterOption{Label: "p99 response time", Value: checkMetricP99},
+					action_kit_api.ExplicitParameterOption{Label: "Throughput (req/s)", Value: checkMetricThroughput},
+				}),
+			},
+			{
+				Name:         "comparison",
+				Label:        "Comparison",
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(checkComparisonAtMost),
+				Required:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "at most", Value: checkComparisonAtMost},
+					action_kit_api.ExplicitParameterOption{Label: "at least", Value: checkComparisonAtLeast},
+				}),
+			},
+			{
+				Name:        "threshold",
+				Label:       "Threshold",
+				Description: new("The value the metric is compared to. Response times in milliseconds or with unit, e.g. \"500ms\" or \"1.5s\"."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+			},
+			{
+				Name:         "conditionCheckMode",
+				Label:        "Condition Check Mode",
+				Description:  new("Whether the condition has to be met all the time or at least once while the check runs."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(checkModeAllTheTime),
+				Required:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "All the time", Value: checkModeAllTheTime},
+					action_kit_api.ExplicitParameterOption{Label: "At least once", Value: checkModeAtLeastOnce},
+				}),
+			},
+			{
+				Name:         "window",
+				Label:        "Evaluation Window",
+				Description:  new("The window the metric is computed over each time it is checked, at most 5 minutes."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("10s"),
+				Required:     new(false),
+				Advanced:     new(true),
+			},
+			{
+				Name:        "simulation",
+				Label:       "Simulation",
+				Description: new("Only check this simulation, by its name in the live statistics. All simulations are checked if omitted."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+		},
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Gatling Metrics Check",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: checkMetricName,
+					From:       "check",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Grouping: new(action_kit_api.LineChartWidgetGroupingConfig{
+					ShowSummary: new(true),
+					Groups: []action_kit_api.LineChartWidgetGroup{
+						{
+							Title: "Condition met",
+							Color: "success",
+							Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+								Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+								Key:   "state",
+								Value: "success",
+							},
+						},
+						{
+							Title: "Condition violated",
+							Color: "danger",
+							Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+								Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+								Key:   "state",
+								Value: "danger",
+							},
+						},
+					},
+				}),
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Value"),
+					AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{
+						{From: "simulation", Title: "Simulation"},
+						{From: "condition", Title: "Condition"},
+					},
+				}),
+			},
+		}),
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("2s"),
+		}),
+	}
+
+	if config.Config.EnableLocationSelection {
+		description.Parameters = append(description.Parameters, action_kit_api.ActionParameter{
+			Name:  "-",
+			Label: "Filter Gatling Locations",
+			Type:  action_kit_api.ActionParameterTypeTargetSelection,
+			Order: new(3),
+		})
+		description.TargetSelection = new(action_kit_api.TargetSelection{
+			TargetType: targetType,
+			DefaultBlastRadius: new(action_kit_api.DefaultBlastRadius{
+				Mode:  action_kit_api.DefaultBlastRadiusModeMaximum,
+				Value: 1,
+			}),
+			MissingQuerySelection: extutil.Ptr(action_kit_api.MissingQuerySelectionIncludeAll),
+		})
+	}
+
+	return description
+}
+
+type GatlingCheckConfig struct {
+	Duration           int
+	Metric             string
+	Comparison         string
+	Threshold          string
+	ConditionCheckMode string
+	Window             *int
+	Simulation         string
+}
+
+func (c *GatlingCheckAction) Prepare(ctx context.Context, state *GatlingCheckState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, request.ExecutionId, "gatling.check.prepare", exttracing.ExecutionContextAttributes(request.ExecutionContext)...)
+	result, err := c.prepare(state, request)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (c *GatlingCheckAction) prepare(state *GatlingCheckState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	var config GatlingCheckConfig
+	if err := extconversion.Convert(request.Config, &config); err != nil {
+		return nil, extension_kit.ToError("Failed to unmarshal the config.", err)
+	}
+	condition, err := parseCheckCondition(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid condition.", err)
+	}
+	if config.Duration <= 0 {
+		return nil, extension_kit.ToError("Invalid duration.", fmt.Errorf("duration must be positive"))
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.ExperimentKey = *request.ExecutionContext.ExperimentKey
+	state.ExperimentExecutionId = *request.ExecutionContext.ExecutionId
+	state.Duration = int64(config.Duration)
+	state.Window = liveWindow.Milliseconds()
+	if config.Window != nil && *config.Window > 0 {
+		state.Window = min(int64(*config.Window), liveHistory.Milliseconds())
+	}
+	state.Simulation = strings.TrimSpace(config.Simulation)
+	state.Condition = condition
+	state.Mode = checkModeAllTheTime
+	if config.ConditionCheckMode == checkModeAtLeastOnce {
+		state.Mode = checkModeAtLeastOnce
+	}
+	return nil, nil
+}
+
+// parseCheckCondition builds the condition from the parameters of the action.
+func parseCheckCondition(config GatlingCheckConfig) (checkCondition, error) {
+	condition := checkCondition{Metric: config.Metric, Comparison: config.Comparison}
+	if condition.Comparison != checkComparisonAtLeast {
+		condition.Comparison = checkComparisonAtMost
+	}
+	value := strings.TrimSpace(config.Threshold)
+	var threshold *float64
+	var err error
+	switch config.Metric {
+	case checkMetricP50, checkMetricP95, checkMetricP99:
+		threshold, err = parseResponseTime(value)
+	case checkMetricErrorPercentage:
+		threshold, err = parseNumber(strings.TrimSuffix(value, "%"))
+	case checkMetricThroughput:
+		threshold, err = parseNumber(value)
+	default:
+		return condition, fmt.Errorf("unknown metric %q", config.Metric)
+	}
+	if err != nil {
+		return condition, fmt.Errorf("invalid threshold %q: %w", config.Threshold, err)
+	}
+	condition.Threshold = *threshold
+	return condition, nil
+}
+
+func (c *GatlingCheckAction) Start(ctx context.Context, state *GatlingCheckState) (*action_kit_api.StartResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.check.start", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := c.start(state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (c *GatlingCheckAction) start(state *GatlingCheckState) (*action_kit_api.StartResult, error) {
+	state.Runs = nil
+	for _, run := range getLiveRuns() {
+		if run.ExperimentKey == state.ExperimentKey && run.ExperimentExecutionId == state.ExperimentExecutionId {
+			state.Runs = append(state.Runs, run.ExecutionId)
+		}
+	}
+	if len(state.Runs) == 0 {
+		return &action_kit_api.StartResult{
+			Error: &action_kit_api.ActionKitError{
+				Status: extutil.Ptr(action_kit_api.Errored),
+				Title:  "No Gatling load test of this experiment execution is running on this extension, start the check after the load test.",
+			},
+		}, nil
+	}
+	state.End = time.Now().Add(time.Duration(state.Duration) * time.Millisecond).UnixMilli()
+	log.Info().Msgf("Checking %d Gatling run(s) until %s", len(state.Runs), time.UnixMilli(state.End).Format(time.RFC3339))
+	return nil, nil
+}
+
+func (c *GatlingCheckAction) Status(ctx context.Context, state *GatlingCheckState) (*action_kit_api.StatusResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.check.status", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result := c.status(state, time.Now())
+	exttracing.End(span, nil)
+	return result, nil
+}
+
+func (c *GatlingCheckAction) status(state *GatlingCheckState, now time.Time) *action_kit_api.StatusResult {
+	stats := map[string]liveStats{}
+	running := false
+	for _, id := range state.Runs {
+		run := getLiveRun(id)
+		if run == nil {
+			continue
+		}
+		running = true
+		for name, s := range run.Simulations(now, time.Duration(state.Window)*time.Millisecond) {
+			if state.Simulation == "" || strings.EqualFold(state.Simulation, name) {
+				stats[name] = s
+			}
+		}
+	}
+
+	result := &action_kit_api.StatusResult{}
+	metrics, violation := state.evaluate(stats, now)
+	result.Metrics = new(metrics)
+	if violation != "" && state.Mode == checkModeAllTheTime {
+		result.Completed = true
+		result.Error = &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Gatling metrics check failed, the %s.", violation),
+		}
+		return result
+	}
+
+	if now.UnixMilli() < state.End && running {
+		return result
+	}
+	result.Completed = true
+	if !running {
+		result.Messages = new([]action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: "The Gatling load test ended before the check.",
+		}})
+	}
+	if !state.Evaluated {
+		result.Error = &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  "Gatling metrics check failed, no requests completed while it ran.",
+		}
+	} else if state.Mode == checkModeAtLeastOnce && !state.Fulfilled {
+		result.Error = &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Gatling metrics check failed, the %s was never %s.", state.Condition.metricLabel(), state.Condition),
+		}
+	}
+	return result
+}
+
+// evaluate checks the condition against the statistics of the simulations.
+// Returns a metric per simulation and the first violation, empty if there is
+// none.
+func (state *GatlingCheckState) evaluate(stats map[string]liveStats, now time.Time) ([]action_kit_api.Metric, string) {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]action_kit_api.Metric, 0, len(names))
+	violation := ""
+	for _, name := range names {
+		value, ok := state.Condition.value(stats[name])
+		if !ok {
+			continue
+		}
+		state.Evaluated = true
+		met := state.Condition.isMet(value)
+		metricState := "success"
+		if met {
+			state.Fulfilled = true
+		} else {
+			metricState = "danger"
+			if violation == "" {
+				violation = fmt.Sprintf("%s of %s was %s%s, expected %s",
+					state.Condition.metricLabel(), name, formatNumber(value), state.Condition.unit(), state.Condition)
+			}
+		}
+		metrics = append(metrics, action_kit_api.Metric{
+			Name: new(checkMetricName),
+			Metric: map[string]string{
+				"check":      fmt.Sprintf("%s: %s", name, state.Condition.metricLabel()),
+				"simulation": name,
+				"condition":  state.Condition.String(),
+				"state":      metricState,
+			},
+			Timestamp: now,
+			Value:     value,
+		})
+	}
+	return metrics, violation
+}
+
+// value returns the metric of the statistics, false if there were no requests
+// in the window to compute it from.
+func (c checkCondition) value(stats liveStats) (float64, bool) {
+	var value float64
+	switch c.Metric {
+	case checkMetricErrorPercentage:
+		value = stats.ErrorRatio * 100
+	case checkMetricP50:
+		value = stats.P50
+	case checkMetricP95:
+		value = stats.P95
+	case checkMetricP99:
+		value = stats.P99
+	case checkMetricThroughput:
+		// no requests is a throughput as well
+		return stats.RequestsPerSecond, true
+	}
+	if stats.Requests == 0 || math.IsNaN(value) {
+		return 0, false
+	}
+	return value, true
+}
+
+func (c checkCondition) isMet(value float64) bool {
+	if c.Comparison == checkComparisonAtLeast {
+		return value >= c.Threshold
+	}
+	return value <= c.Threshold
+}
+
+func (c checkCondition) metricLabel() string {
+	switch c.Metric {
+	case checkMetricErrorPercentage:
+		return "error percentage"
+	case checkMetricThroughput:
+		return "throughput"
+	default:
+		return c.Metric + " response time"
+	}
+}
+
+func (c checkCondition) unit() string {
+	switch c.Metric {
+	case checkMetricErrorPercentage:
+		return "%"
+	case checkMetricThroughput:
+		return " req/s"
+	default:
+		return " ms"
+	}
+}
+
+// String describes the expectation, e.g. "at most 500 ms".
+func (c checkCondition) String() string {
+	comparison := "at most"
+	if c.Comparison == checkComparisonAtLeast {
+		comparison = "at least"
+	}
+	return fmt.Sprintf("%s %s%s", comparison, formatNumber(c.Threshold), c.unit())
+}
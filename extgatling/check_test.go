@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCheckCondition(t *testing.T) {
+	condition, err := parseCheckCondition(GatlingCheckConfig{Metric: checkMetricP95, Comparison: checkComparisonAtMost, Threshold: "1.5s"})
+	require.NoError(t, err)
+	assert.Equal(t, checkCondition{Metric: checkMetricP95, Comparison: checkComparisonAtMost, Threshold: 1500}, condition)
+	assert.Equal(t, "at most 1500 ms", condition.String())
+
+	condition, err = parseCheckCondition(GatlingCheckConfig{Metric: checkMetricErrorPercentage, Threshold: "0.5%"})
+	require.NoError(t, err)
+	assert.Equal(t, "at most 0.5%", condition.String())
+
+	condition, err = parseCheckCondition(GatlingCheckConfig{Metric: checkMetricThroughput, Comparison: checkComparisonAtLeast, Threshold: "20"})
+	require.NoError(t, err)
+	assert.Equal(t, "at least 20 req/s", condition.String())
+
+	_, err = parseCheckCondition(GatlingCheckConfig{Metric: "p42", Threshold: "1"})
+	assert.ErrorContains(t, err, "unknown metric")
+	_, err = parseCheckCondition(GatlingCheckConfig{Metric: checkMetricP99, Threshold: "soon"})
+	assert.ErrorContains(t, err, "invalid threshold")
+}
+
+func Test_GatlingCheckAction_start_requires_a_run_of_the_experiment_execution(t *testing.T) {
+	run := addCheckedRun(t, "ADM-1", 42)
+	addCheckedRun(t, "ADM-1", 43)
+	action := &GatlingCheckAction{}
+
+	state := &GatlingCheckState{ExecutionId: uuid.New(), ExperimentKey: "ADM-1", ExperimentExecutionId: 42, Duration: 30000}
+	result, err := action.Start(context.Background(), state)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, []uuid.UUID{run.ExecutionId}, state.Runs)
+
+	state = &GatlingCheckState{ExecutionId: uuid.New(), ExperimentKey: "ADM-2", ExperimentExecutionId: 42, Duration: 30000}
+	result, err = action.Start(context.Background(), state)
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, action_kit_api.Errored, *result.Error.Status)
+}
+
+func Test_GatlingCheckAction_fails_once_violated_all_the_time(t *testing.T) {
+	now := time.Now()
+	run := addCheckedRun(t, "ADM-1", 42)
+	setLiveRequests(run, "basicsimulation", now, 9, 1, 100)
+	setLiveRequests(run, "othersimulation", now, 10, 0, 100)
+	state := &GatlingCheckState{
+		Condition: checkCondition{Metric: checkMetricErrorPercentage, Comparison: checkComparisonAtMost, Threshold: 5},
+		Mode:      checkModeAllTheTime,
+		Window:    10000,
+		End:       now.Add(time.Minute).UnixMilli(),
+		Runs:      []uuid.UUID{run.ExecutionId},
+	}
+
+	result := (&GatlingCheckAction{}).status(state, now)
+
+	assert.True(t, result.Completed)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, action_kit_api.Failed, *result.Error.Status)
+	assert.Equal(t, "Gatling metrics check failed, the error percentage of basicsimulation was 10%, expected at most 5%.", result.Error.Title)
+	require.Len(t, *result.Metrics, 2)
+	metric := (*result.Metrics)[0]
+	assert.Equal(t, map[string]string{
+		"check":      "basicsimulation: error percentage",
+		"simulation": "basicsimulation",
+		"condition":  "at most 5%",
+		"state":      "danger",
+	}, metric.Metric)
+	assert.InDelta(t, 10, metric.Value, 0.001)
+	assert.Equal(t, "success", (*result.Metrics)[1].Metric["state"])
+}
+
+func Test_GatlingCheckAction_succeeds_at_the_end(t *testing.T) {
+	now := time.Now()
+	run := addCheckedRun(t, "ADM-1", 42)
+	setLiveRequests(run, "basicsimulation", now, 10, 0, 100)
+	state := &GatlingCheckState{
+		Condition:  checkCondition{Metric: checkMetricP95, Comparison: checkComparisonAtMost, Threshold: 200},
+		Mode:       checkModeAllTheTime,
+		Window:     10000,
+		End:        now.Add(time.Second).UnixMilli(),
+		Runs:       []uuid.UUID{run.ExecutionId},
+		Simulation: "BasicSimulation",
+	}
+	action := &GatlingCheckAction{}
+
+	result := action.status(state, now)
+	assert.False(t, result.Completed)
+	assert.Nil(t, result.Error)
+
+	result = action.status(state, now.Add(time.Second))
+	assert.True(t, result.Completed)
+	assert.Nil(t, result.Error)
+}
+
+func Test_GatlingCheckAction_at_least_once(t *testing.T) {
+	now := time.Now()
+	run := addCheckedRun(t, "ADM-1", 42)
+	setLiveRequests(run, "basicsimulation", now, 10, 0, 100)
+	state := &GatlingCheckState{
+		Condition: checkCondition{Metric: checkMetricThroughput, Comparison: checkComparisonAtLeast, Threshold: 20},
+		Mode:      checkModeAtLeastOnce,
+		Window:    10000,
+		End:       now.UnixMilli(),
+		Runs:      []uuid.UUID{run.ExecutionId},
+	}
+
+	result := (&GatlingCheckAction{}).status(state, now)
+
+	assert.True(t, result.Completed)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Gatling metrics check failed, the throughput was never at least 20 req/s.", result.Error.Title)
+}
+
+func Test_GatlingCheckAction_completes_when_the_run_ended(t *testing.T) {
+	state := &GatlingCheckState{
+		Condition: checkCondition{Metric: checkMetricP99, Comparison: checkComparisonAtMost, Threshold: 200},
+		Mode:      checkModeAllTheTime,
+		Window:    10000,
+		End:       time.Now().Add(time.Minute).UnixMilli(),
+		Runs:      []uuid.UUID{uuid.New()},
+	}
+
+	result := (&GatlingCheckAction{}).status(state, time.Now())
+
+	assert.True(t, result.Completed)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Gatling metrics check failed, no requests completed while it ran.", result.Error.Title)
+	assert.Equal(t, "The Gatling load test ended before the check.", (*result.Messages)[0].Message)
+}
+
+// addCheckedRun registers a live run of the experiment execution, removed again
+// after the test.
+func addCheckedRun(t *testing.T, experimentKey string, experimentExecutionId int) *liveRun {
+	run := &liveRun{
+		ExecutionId:           uuid.New(),
+		ExperimentKey:         experimentKey,
+		ExperimentExecutionId: experimentExecutionId,
+		simulations:           map[string]*liveSimulation{},
+	}
+	liveRunsMu.Lock()
+	liveRuns[run.ExecutionId] = run
+	liveRunsMu.Unlock()
+	t.Cleanup(func() {
+		liveRunsMu.Lock()
+		delete(liveRuns, run.ExecutionId)
+		liveRunsMu.Unlock()
+	})
+	return run
+}
+
+// setLiveRequests records ok and ko requests with the given response time for
+// each of the last ten seconds.
+func setLiveRequests(run *liveRun, name string, now time.Time, ok, ko, responseTime int64) {
+	simulation := run.simulation(name)
+	for i := int64(0); i < 10; i++ {
+		sample := simulation.sample(now.Unix() - i)
+		sample.Ok, sample.Ko = ok, ko
+		for j := int64(0); j < ok+ko; j++ {
+			sample.ResponseTimes = append(sample.ResponseTimes, responseTime)
+		}
+	}
+}
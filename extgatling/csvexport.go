@@ -0,0 +1,238 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// histogramBuckets are the upper bounds in milliseconds of the response time
+// histogram, the last bucket is open-ended.
+var histogramBuckets = []int64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// timeSeriesRow are the statistics of the requests that ended in one second.
+// Response times are in milliseconds, NaN if there was no request.
+type timeSeriesRow struct {
+	Second int64 // epoch millis of the start of the second
+	Ok     int64
+	Ko     int64
+	P50    float64
+	P95    float64
+	P99    float64
+}
+
+// histogramRow is how many responses of a request took from From, inclusive,
+// to To milliseconds, exclusive. To is -1 for the open-ended last bucket.
+type histogramRow struct {
+	Request string
+	From    int64
+	To      int64
+	Count   int64
+}
+
+// csvSecondSlack is how far behind the latest request a second of the time
+// series is still open. Gatling writes the requests about in the order they
+// ended, a request ending in a second closed already only adds to its counts.
+const csvSecondSlack = 10 * 1000
+
+// csvAggregator aggregates the requests of a simulation.log per second and
+// into a response time histogram per request. Only the seconds within
+// csvSecondSlack of the latest request keep their response times.
+type csvAggregator struct {
+	open    map[int64]*openSecond
+	rows    map[int64]*timeSeriesRow
+	latest  int64
+	buckets map[string][]int64
+}
+
+type openSecond struct {
+	ko            int64
+	responseTimes responseTimeHistogram
+}
+
+func newCsvAggregator() *csvAggregator {
+	return &csvAggregator{open: map[int64]*openSecond{}, rows: map[int64]*timeSeriesRow{}, buckets: map[string][]int64{}}
+}
+
+func (a *csvAggregator) visit(record any) {
+	request, ok := record.(*requestRecord)
+	if !ok {
+		return
+	}
+	responseTime := request.End - request.Start
+	key := request.End - request.End%1000
+	if row, closed := a.rows[key]; closed {
+		if request.Ok {
+			row.Ok++
+		} else {
+			row.Ko++
+		}
+	} else {
+		s, found := a.open[key]
+		if !found {
+			s = &openSecond{}
+			a.open[key] = s
+		}
+		s.responseTimes.add(responseTime)
+		if !request.Ok {
+			s.ko++
+		}
+	}
+	if key > a.latest {
+		a.latest = key
+		a.closeSeconds(a.latest - csvSecondSlack)
+	}
+
+	name := requestPath(request)
+	counts, found := a.buckets[name]
+	if !found {
+		counts = make([]int64, len(histogramBuckets)+1)
+		a.buckets[name] = counts
+	}
+	counts[sort.Search(len(histogramBuckets), func(i int) bool { return responseTime < histogramBuckets[i] })]++
+}
+
+// closeSeconds turns the open seconds before until into rows.
+func (a *csvAggregator) closeSeconds(until int64) {
+	for key, s := range a.open {
+		if key >= until {
+			continue
+		}
+		a.rows[key] = &timeSeriesRow{
+			Second: key,
+			Ok:     s.responseTimes.total - s.ko,
+			Ko:     s.ko,
+			P50:    s.responseTimes.percentile(50),
+			P95:    s.responseTimes.percentile(95),
+			P99:    s.responseTimes.percentile(99),
+		}
+		delete(a.open, key)
+	}
+}
+
+// result returns the time series and the histogram. Seconds without requests
+// between the first and the last are included, so the series has no gaps.
+func (a *csvAggregator) result() ([]timeSeriesRow, []histogramRow) {
+	a.closeSeconds(math.MaxInt64)
+	var series []timeSeriesRow
+	if len(a.rows) > 0 {
+		first, last := int64(math.MaxInt64), int64(math.MinInt64)
+		for key := range a.rows {
+			first, last = min(first, key), max(last, key)
+		}
+		for key := first; key <= last; key += 1000 {
+			if row, found := a.rows[key]; found {
+				series = append(series, *row)
+			} else {
+				series = append(series, timeSeriesRow{Second: key, P50: math.NaN(), P95: math.NaN(), P99: math.NaN()})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(a.buckets))
+	for name := range a.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var histogram []histogramRow
+	for _, name := range names {
+		from := int64(0)
+		for i, count := range a.buckets[name] {
+			to := int64(-1)
+			if i < len(histogramBuckets) {
+				to = histogramBuckets[i]
+			}
+			histogram = append(histogram, histogramRow{Request: name, From: from, To: to, Count: count})
+			from = to
+		}
+	}
+	return series, histogram
+}
+
+// timeSeriesCsv renders the per-second statistics as CSV.
+func timeSeriesCsv(series []timeSeriesRow) []byte {
+	rows := [][]string{{"time", "requests", "ok", "ko", "error_percentage", "p50_ms", "p95_ms", "p99_ms"}}
+	for _, row := range series {
+		requests := row.Ok + row.Ko
+		errorPercentage := ""
+		if requests > 0 {
+			errorPercentage = formatNumber(float64(row.Ko) * 100 / float64(requests))
+		}
+		rows = append(rows, []string{
+			time.UnixMilli(row.Second).UTC().Format(time.RFC3339),
+			strconv.FormatInt(requests, 10),
+			strconv.FormatInt(row.Ok, 10),
+			strconv.FormatInt(row.Ko, 10),
+			errorPercentage,
+			csvNumber(row.P50),
+			csvNumber(row.P95),
+			csvNumber(row.P99),
+		})
+	}
+	return csvBytes(rows)
+}
+
+// histogramCsv renders the response time histogram as CSV, one row per
+// request and bucket.
+func histogramCsv(histogram []histogramRow) []byte {
+	rows := [][]string{{"request", "from_ms", "to_ms", "count"}}
+	for _, row := range histogram {
+		to := ""
+		if row.To >= 0 {
+			to = strconv.FormatInt(row.To, 10)
+		}
+		rows = append(rows, []string{row.Request, strconv.FormatInt(row.From, 10), to, strconv.FormatInt(row.Count, 10)})
+	}
+	return csvBytes(rows)
+}
+
+// csvNumber formats a value for CSV, leaving the cell empty if there is none.
+func csvNumber(value float64) string {
+	if math.IsNaN(value) {
+		return ""
+	}
+	return formatNumber(value)
+}
+
+func csvBytes(rows [][]string) []byte {
+	var buf bytes.Buffer
+	// writing to a buffer doesn't fail
+	_ = csv.NewWriter(&buf).WriteAll(rows)
+	return buf.Bytes()
+}
+
+// csvArtifacts returns the per-second time series and the response time
+// histogram of an analyzed report as CSV artifacts.
+func csvArtifacts(reportDir string, analysis *reportAnalysis) ([]action_kit_api.Artifact, []action_kit_api.Message) {
+	name := filepath.Base(reportDir)
+	if analysis == nil || analysis.csv == nil || analysis.err != nil {
+		return nil, []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("No CSV files attached for report %s, its simulation.log could not be read.", name),
+		}}
+	}
+	series, histogram := analysis.csv.result()
+	return []action_kit_api.Artifact{
+		{
+			Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_timeseries.csv", name),
+			Data:  base64.StdEncoding.EncodeToString(timeSeriesCsv(series)),
+		},
+		{
+			Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_histogram.csv", name),
+			Data:  base64.StdEncoding.EncodeToString(histogramCsv(histogram)),
+		},
+	}, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_csvAggregator(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start, start+100, true, "").
+		request(nil, "home", start+200, start+500, true, "").
+		request([]string{"checkout"}, "pay", start+100, start+150, false, "status.find.is(200), but actually found 500").
+		request(nil, "home", start+2000, start+2020, true, "").
+		bytes()
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), content, 0644))
+
+	aggregator := newCsvAggregator()
+	require.NoError(t, readSimulationLog(filepath.Join(reportDir, "simulation.log"), aggregator.visit))
+	series, histogram := aggregator.result()
+
+	assert.Equal(t, "time,requests,ok,ko,error_percentage,p50_ms,p95_ms,p99_ms\n"+
+		"2026-01-01T12:00:00Z,3,2,1,33.33,100,300,300\n"+
+		"2026-01-01T12:00:01Z,0,0,0,,,,\n"+
+		"2026-01-01T12:00:02Z,1,1,0,0,20,20,20\n", string(timeSeriesCsv(series)))
+	assert.Contains(t, histogram, histogramRow{Request: "checkout / pay", From: 50, To: 100, Count: 1})
+	assert.Contains(t, histogram, histogramRow{Request: "home", From: 250, To: 500, Count: 1})
+	assert.Contains(t, histogram, histogramRow{Request: "home", From: 10000, To: -1, Count: 0})
+	assert.Len(t, histogram, 2*(len(histogramBuckets)+1))
+
+	csv := string(histogramCsv(histogram))
+	assert.Contains(t, csv, "request,from_ms,to_ms,count\ncheckout / pay,0,10,0\n")
+	assert.Contains(t, csv, "home,100,250,1\n")
+	assert.Contains(t, csv, "home,10000,,0\n")
+}
+
+func Test_csvArtifacts(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"),
+		newSimulationLogWriter("BasicSimulation", start, "Users").request(nil, "home", start, start+100, true, "").bytes(), 0644))
+
+	artifacts, messages := csvArtifacts(reportDir, analyzeReports([]string{reportDir}, nil, true)[reportDir])
+
+	assert.Empty(t, messages)
+	require.Len(t, artifacts, 2)
+	assert.Equal(t, "$(experimentKey)_$(executionId)_basicsimulation-1_timeseries.csv", artifacts[0].Label)
+	assert.Equal(t, "$(experimentKey)_$(executionId)_basicsimulation-1_histogram.csv", artifacts[1].Label)
+	data, err := base64.StdEncoding.DecodeString(artifacts[1].Data)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "home,100,250,1\n")
+}
+
+func Test_csvArtifacts_without_simulation_log(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	artifacts, messages := csvArtifacts(reportDir, analyzeReports([]string{reportDir}, nil, true)[reportDir])
+
+	assert.Empty(t, artifacts)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "No CSV files attached for report basicsimulation-1, its simulation.log could not be read.", messages[0].Message)
+}
+
+func Test_csvAggregator_counts_requests_of_closed_seconds(t *testing.T) {
+	start := int64(1767268800000)
+	aggregator := newCsvAggregator()
+	aggregator.visit(&requestRecord{Name: "home", Start: start, End: start + 100, Ok: true})
+	aggregator.visit(&requestRecord{Name: "home", Start: start + 20000, End: start + 20100, Ok: true})
+	assert.Len(t, aggregator.open, 1, "the first second is closed")
+	aggregator.visit(&requestRecord{Name: "home", Start: start, End: start + 900, Ok: false})
+
+	series, _ := aggregator.result()
+
+	require.Len(t, series, 21)
+	assert.Equal(t, timeSeriesRow{Second: start, Ok: 1, Ko: 1, P50: 100, P95: 100, P99: 100}, series[0])
+}
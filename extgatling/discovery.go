@@ -11,10 +11,16 @@ import (
 	"github.com/steadybit/extension-gatling/config"
 	"github.com/steadybit/extension-kit/extbuild"
 	"os"
+	"strconv"
+	"time"
 )
 
 type gatlingLocationDiscovery struct{}
 
+// discoveryRefreshInterval is how often the location is discovered again, to
+// keep its number of active runs current.
+const discoveryRefreshInterval = 10 * time.Second
+
 var (
 	_ discovery_kit_sdk.TargetDescriber = (*gatlingLocationDiscovery)(nil)
 )
@@ -23,7 +29,8 @@ func NewDiscovery() discovery_kit_sdk.TargetDiscovery {
 	discovery := &gatlingLocationDiscovery{}
 	return discovery_kit_sdk.NewCachedTargetDiscovery(discovery,
 		discovery_kit_sdk.WithRefreshTargetsNow(),
-		//No interval, target is not changing during runtime
+		// the active runs of the location change
+		discovery_kit_sdk.WithRefreshTargetsInterval(context.Background(), discoveryRefreshInterval),
 	)
 }
 
@@ -31,7 +38,7 @@ func (e *gatlingLocationDiscovery) Describe() discovery_kit_api.DiscoveryDescrip
 	return discovery_kit_api.DiscoveryDescription{
 		Id: targetType,
 		Discover: discovery_kit_api.DescribingEndpointReferenceWithCallInterval{
-			CallInterval: new(fmt.Sprintf("%ds", 300)),
+			CallInterval: new("30s"),
 		},
 	}
 }
@@ -49,7 +56,11 @@ func (e *gatlingLocationDiscovery) DescribeTarget() discovery_kit_api.TargetDesc
 				{Attribute: "k8s.cluster-name"},
 				{Attribute: "k8s.namespace"},
 				{Attribute: "aws.account", FallbackAttributes: &[]string{"gcp.project.id", "azure.subscription.id"}},
-				{Attribute: "aws.zone", FallbackAttributes: &[]string{"gcp.zone", "azure.zone"}},
+				{Attribute: "aws.zone", FallbackAttributes: &[]string{"gcp.zone", "azure.zone", "gatling.location.zone"}},
+				{Attribute: "gatling.location.cpus"},
+				{Attribute: "gatling.location.memory.mb"},
+				{Attribute: "gatling.location.active-runs"},
+				{Attribute: "gatling.location.queued-runs"},
 			},
 			OrderBy: []discovery_kit_api.OrderBy{
 				{
@@ -61,7 +72,53 @@ func (e *gatlingLocationDiscovery) DescribeTarget() discovery_kit_api.TargetDesc
 	}
 }
 
+func (e *gatlingLocationDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescription {
+	return []discovery_kit_api.AttributeDescription{
+		{
+			Attribute: "gatling.location.cpus",
+			Label:     discovery_kit_api.PluralLabel{One: "CPU Cores", Other: "CPU Cores"},
+		},
+		{
+			Attribute: "gatling.location.memory.mb",
+			Label:     discovery_kit_api.PluralLabel{One: "Memory (MB)", Other: "Memory (MB)"},
+		},
+		{
+			Attribute: "gatling.location.java.version",
+			Label:     discovery_kit_api.PluralLabel{One: "Java Version", Other: "Java Versions"},
+		},
+		{
+			Attribute: "gatling.location.gatling.version",
+			Label:     discovery_kit_api.PluralLabel{One: "Gatling Version", Other: "Gatling Versions"},
+		},
+		{
+			Attribute: "gatling.location.active-runs",
+			Label:     discovery_kit_api.PluralLabel{One: "Active Runs", Other: "Active Runs"},
+		},
+		{
+			Attribute: "gatling.location.queued-runs",
+			Label:     discovery_kit_api.PluralLabel{One: "Queued Runs", Other: "Queued Runs"},
+		},
+		{
+			Attribute: "gatling.location.region",
+			Label:     discovery_kit_api.PluralLabel{One: "Region", Other: "Regions"},
+		},
+		{
+			Attribute: "gatling.location.zone",
+			Label:     discovery_kit_api.PluralLabel{One: "Zone", Other: "Zones"},
+		},
+		{
+			Attribute: "gatling.location.weight",
+			Label:     discovery_kit_api.PluralLabel{One: "Load Weight", Other: "Load Weights"},
+		},
+	}
+}
+
 func (e *gatlingLocationDiscovery) DiscoverTargets(_ context.Context) ([]discovery_kit_api.Target, error) {
+	return []discovery_kit_api.Target{selfLocation()}, nil
+}
+
+// selfLocation is the location target of this extension instance.
+func selfLocation() discovery_kit_api.Target {
 	attributes := make(map[string][]string)
 
 	var id, label string
@@ -85,12 +142,35 @@ func (e *gatlingLocationDiscovery) DiscoverTargets(_ context.Context) ([]discove
 		attributes["k8s.cluster-name"] = []string{config.Config.KubernetesClusterName}
 	}
 
-	return []discovery_kit_api.Target{
-		{
-			Id:         id,
-			Label:      label,
-			TargetType: targetType,
-			Attributes: attributes,
-		},
-	}, nil
+	resources := currentLocationResources()
+	attributes["gatling.location.cpus"] = []string{formatNumber(resources.Cpus)}
+	if resources.MemoryMb > 0 {
+		attributes["gatling.location.memory.mb"] = []string{fmt.Sprintf("%d", resources.MemoryMb)}
+	}
+	if resources.JavaVersion != "" {
+		attributes["gatling.location.java.version"] = []string{resources.JavaVersion}
+	}
+	if resources.GatlingVersion != "" {
+		attributes["gatling.location.gatling.version"] = []string{resources.GatlingVersion}
+	}
+	if config.Config.LocationRegion != "" {
+		attributes["gatling.location.region"] = []string{config.Config.LocationRegion}
+	}
+	if config.Config.LocationZone != "" {
+		attributes["gatling.location.zone"] = []string{config.Config.LocationZone}
+	}
+	active, queued := runCounts(time.Now())
+	attributes["gatling.location.active-runs"] = []string{strconv.Itoa(active)}
+	attributes["gatling.location.queued-runs"] = []string{strconv.Itoa(queued)}
+	attributes["gatling.location.weight"] = []string{formatNumber(config.Config.LocationWeight)}
+	for key, value := range config.Config.LocationLabels {
+		attributes["gatling.location.label."+key] = []string{value}
+	}
+
+	return discovery_kit_api.Target{
+		Id:         id,
+		Label:      label,
+		TargetType: targetType,
+		Attributes: attributes,
+	}
 }
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-gatling/extmetrics"
+)
+
+// graphiteRootPath is the rootPathPrefix Gatling is configured with, the
+// series are named <root>.<simulation>.<path>.
+const graphiteRootPath = "gatling"
+
+// graphiteReceiver accepts the Graphite plaintext protocol Gatling's graphite
+// data writer speaks, lines of "<path> <value> <epoch seconds>", and records
+// the series in the live run. Gatling sends the statistics of the last write
+// period, one second, so counts and percentiles are per second.
+type graphiteReceiver struct {
+	run      *liveRun
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// listenGraphite starts a receiver for the run on a random local port.
+func listenGraphite(run *liveRun) (*graphiteReceiver, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	g := &graphiteReceiver{run: run, listener: listener, conns: map[net.Conn]struct{}{}}
+	g.wg.Add(1)
+	go g.accept()
+	return g, nil
+}
+
+// Port is the local port the receiver listens on.
+func (g *graphiteReceiver) Port() int {
+	return g.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Close stops accepting connections and closes the open ones.
+func (g *graphiteReceiver) Close() {
+	_ = g.listener.Close()
+	g.mu.Lock()
+	for conn := range g.conns {
+		_ = conn.Close()
+	}
+	g.mu.Unlock()
+	g.wg.Wait()
+}
+
+func (g *graphiteReceiver) accept() {
+	defer g.wg.Done()
+	for {
+		conn, err := g.listener.Accept()
+		if err != nil {
+			return // closed
+		}
+		g.mu.Lock()
+		g.conns[conn] = struct{}{}
+		g.mu.Unlock()
+		g.wg.Add(1)
+		go g.serve(conn)
+	}
+}
+
+func (g *graphiteReceiver) serve(conn net.Conn) {
+	defer g.wg.Done()
+	defer func() {
+		g.mu.Lock()
+		delete(g.conns, conn)
+		g.mu.Unlock()
+		_ = conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		if err := g.run.recordGraphite(scanner.Text()); err != nil {
+			log.Debug().Err(err).Msg("Ignoring graphite line")
+		}
+	}
+}
+
+// recordGraphite records one line sent by Gatling. Only the series of all
+// requests and all users are of interest, Gatling is configured not to send
+// the ones per request.
+func (r *liveRun) recordGraphite(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return fmt.Errorf("malformed line %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return fmt.Errorf("malformed value in %q", line)
+	}
+	second, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed timestamp in %q", line)
+	}
+	path := strings.Split(fields[0], ".")
+	if len(path) < 5 || path[0] != graphiteRootPath {
+		return fmt.Errorf("unexpected path %q", fields[0])
+	}
+	name, series := path[1], strings.Join(path[2:], ".")
+
+	simulation := r.simulation(name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch series {
+	case "users.allUsers.active":
+		simulation.ActiveUsers = int64(value)
+		r.observeStartup(false)
+	case "allRequests.ok.count":
+		simulation.Ok += int64(value)
+		simulation.sample(second).Ok += int64(value)
+		r.observeStartup(value > 0)
+	case "allRequests.ko.count":
+		simulation.Ko += int64(value)
+		simulation.sample(second).Ko += int64(value)
+		r.observeStartup(value > 0)
+	case "allRequests.all.percentiles50":
+		simulation.sample(second).reported().P50 = value
+	case "allRequests.all.percentiles95":
+		simulation.sample(second).reported().P95 = value
+	case "allRequests.all.percentiles99":
+		simulation.sample(second).reported().P99 = value
+	}
+	return nil
+}
+
+// observeStartup records the startup durations the first time Gatling sends
+// anything and the first time it sends completed requests.
+func (r *liveRun) observeStartup(requestCompleted bool) {
+	now := time.Now()
+	if !r.simulationStarted {
+		r.simulationStarted = true
+		extmetrics.ObserveCompileDuration(now.Sub(r.started))
+	}
+	if !r.requestCompleted && requestCompleted {
+		r.requestCompleted = true
+		extmetrics.ObserveTimeToFirstRequest(now.Sub(r.started))
+	}
+}
+
+// writeGraphiteConfig appends the settings for the graphite data writer to the
+// gatling.conf of the scaffold. Later definitions override earlier ones in
+// HOCON, so the rest of the file is left as is.
+func writeGraphiteConfig(gatlingConf string, port int) error {
+	f, err := os.OpenFile(gatlingConf, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, `
+# added by the extension to stream the live statistics to it
+gatling.data.writers = [console, file, graphite]
+gatling.data.graphite.light = true
+gatling.data.graphite.host = "127.0.0.1"
+gatling.data.graphite.port = %d
+gatling.data.graphite.protocol = "tcp"
+gatling.data.graphite.rootPathPrefix = %q
+gatling.data.graphite.writePeriod = 1
+`, port, graphiteRootPath)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// graphiteProperties are the settings of writeGraphiteConfig as system
+// properties, for a run compiled in Prepare, whose gatling.conf was already
+// copied to the test classes. Gatling lets them override gatling.conf, the
+// list of writers is given by index.
+func graphiteProperties(port int) []string {
+	return []string{
+		"-Dgatling.data.writers.0=console",
+		"-Dgatling.data.writers.1=file",
+		"-Dgatling.data.writers.2=graphite",
+		"-Dgatling.data.graphite.light=true",
+		"-Dgatling.data.graphite.host=127.0.0.1",
+		fmt.Sprintf("-Dgatling.data.graphite.port=%d", port),
+		"-Dgatling.data.graphite.protocol=tcp",
+		fmt.Sprintf("-Dgatling.data.graphite.rootPathPrefix=%s", graphiteRootPath),
+		"-Dgatling.data.graphite.writePeriod=1",
+	}
+}
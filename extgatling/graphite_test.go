@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_graphiteReceiver_records_the_series(t *testing.T) {
+	state := &GatlingLoadTestRunState{ExecutionId: uuid.New(), ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+	run, err := startLiveRun(state, t.TempDir())
+	require.NoError(t, err)
+	defer stopLiveRun(state.ExecutionId)
+	require.NotNil(t, run.graphite)
+
+	now := time.Now().Unix()
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", run.graphite.Port()))
+	require.NoError(t, err)
+	for second := now - 1; second <= now; second++ {
+		_, err = fmt.Fprintf(conn, strings.Join([]string{
+			"gatling.basicsimulation.users.allUsers.active 4 %[1]d",
+			"gatling.basicsimulation.users.allUsers.done 1 %[1]d",
+			"gatling.basicsimulation.allRequests.ok.count 9 %[1]d",
+			"gatling.basicsimulation.allRequests.ko.count 1 %[1]d",
+			"gatling.basicsimulation.allRequests.all.percentiles50 20 %[1]d",
+			"gatling.basicsimulation.allRequests.all.percentiles95 %[2]d %[1]d",
+			"gatling.basicsimulation.allRequests.all.percentiles99 200 %[1]d",
+			"malformed",
+			"",
+		}, "\n"), second, 100+100*(now-second))
+		require.NoError(t, err)
+	}
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return run.Simulations(time.Now(), liveWindow)["basicsimulation"].TotalOk == 18
+	}, 2*time.Second, 10*time.Millisecond)
+	stats := run.Simulations(time.Unix(now, 0), liveWindow)["basicsimulation"]
+	assert.Equal(t, int64(2), stats.TotalKo)
+	assert.Equal(t, int64(20), stats.Requests)
+	assert.Equal(t, int64(4), stats.ActiveUsers)
+	assert.InDelta(t, 0.1, stats.ErrorRatio, 0.001)
+	assert.InDelta(t, 20, stats.P50, 0.001)
+	assert.InDelta(t, 150, stats.P95, 0.001)
+}
+
+func Test_recordGraphite_ignores_other_series(t *testing.T) {
+	run := &liveRun{simulations: map[string]*liveSimulation{}, reports: map[string]string{}}
+
+	assert.Error(t, run.recordGraphite("other.basicsimulation.allRequests.ok.count 1 100"))
+	assert.Error(t, run.recordGraphite("gatling.basicsimulation.allRequests.ok.count x 100"))
+	assert.NoError(t, run.recordGraphite("gatling.basicsimulation.home.ok.count 1 100"))
+	assert.Zero(t, run.simulations["basicsimulation"].Ok)
+}
+
+func Test_startLiveRun_leaves_the_compile_duration_to_prepare_with_a_synchronized_start(t *testing.T) {
+	state := &GatlingLoadTestRunState{ExecutionId: uuid.New(), Sync: &syncStart{Locations: 2}}
+	run, err := startLiveRun(state, t.TempDir())
+	require.NoError(t, err)
+	defer stopLiveRun(state.ExecutionId)
+
+	assert.True(t, run.simulationStarted)
+}
+
+func Test_writeGraphiteConfig(t *testing.T) {
+	gatlingConf := filepath.Join(t.TempDir(), "gatling.conf")
+	require.NoError(t, os.WriteFile(gatlingConf, []byte("gatling {\n}\n"), 0644))
+
+	require.NoError(t, writeGraphiteConfig(gatlingConf, 12345))
+
+	content, err := os.ReadFile(gatlingConf)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "gatling {\n}\n"))
+	assert.Contains(t, string(content), "gatling.data.writers = [console, file, graphite]\n")
+	assert.Contains(t, string(content), "gatling.data.graphite.port = 12345\n")
+	assert.Contains(t, string(content), "gatling.data.graphite.rootPathPrefix = \"gatling\"\n")
+}
+
+func Test_graphiteProperties(t *testing.T) {
+	properties := graphiteProperties(12345)
+
+	assert.Subset(t, properties, []string{
+		"-Dgatling.data.writers.0=console",
+		"-Dgatling.data.writers.2=graphite",
+		"-Dgatling.data.graphite.port=12345",
+		"-Dgatling.data.graphite.rootPathPrefix=gatling",
+	})
+}
+
+func Test_liveMessages(t *testing.T) {
+	now := time.Unix(1767268800, 0)
+	run := &liveRun{simulations: map[string]*liveSimulation{
+		"b": {Ok: 5, ActiveUsers: 1},
+		"a": {Ok: 90, Ko: 10, ActiveUsers: 3},
+	}}
+	sample := run.simulations["a"].sample(now.Unix())
+	sample.Ok = 45
+	sample.Ko = 5
+	sample.ResponseTimes = []int64{120}
+
+	messages := liveMessages(run, now)
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, "a: 100 requests (10 KO), last 10s: 5.0 req/s, 10.0% errors, p95 120 ms, 3 active users", messages[0].Message)
+	assert.Equal(t, "b: 5 requests (0 KO), last 10s: 0.0 req/s, 0.0% errors, p95 -, 1 active users", messages[1].Message)
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-gatling/config"
+)
+
+const megabyte = 1024 * 1024
+
+// errArtifactTooLarge is returned by zipReport if the report does not fit into
+// the artifact size limit, not even without its simulation.log.
+var errArtifactTooLarge = errors.New("report exceeds the artifact size limit")
+
+// checkFreeDiskSpace fails if the file system holding path has less space
+// available than configured. A limit of zero or less disables the check.
+func checkFreeDiskSpace(path string) error {
+	minFreeMb := config.Config.MinFreeDiskSpaceMb
+	if minFreeMb <= 0 {
+		return nil
+	}
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return err
+	}
+	free := int64(stat.Bavail) * int64(stat.Bsize)
+	if free < minFreeMb*megabyte {
+		return fmt.Errorf("only %s available in %s, at least %d MB are required", formatSize(free), path, minFreeMb)
+	}
+	return nil
+}
+
+// reportSizeExceeded reports whether the report folder of a running simulation
+// has grown beyond the configured limit, along with its current size.
+func reportSizeExceeded(reportFolder string) (int64, bool) {
+	maxSizeMb := config.Config.MaxReportSizeMb
+	if maxSizeMb <= 0 {
+		return 0, false
+	}
+	size, err := dirSize(reportFolder)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to determine the size of %s", reportFolder)
+		return 0, false
+	}
+	return size, size > maxSizeMb*megabyte
+}
+
+// dirSize returns the summed size of all regular files below dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// zipReport zips the report folder dir into dst, keeping the archive below
+// maxSizeMb megabytes. simulation.log makes up the bulk of a long run's report,
+// so if the report does not fit, it is zipped once more without it and trimmed
+// is reported. If even the trimmed archive is too large, dst is removed and
+// errArtifactTooLarge is returned. A limit of zero or less disables the check.
+func zipReport(dir, dst string, maxSizeMb int64) (trimmed bool, err error) {
+	if maxSizeMb <= 0 {
+		return false, zipDir(dir, dst)
+	}
+	maxSize := maxSizeMb * megabyte
+
+	// Don't even try to compress a report whose simulation.log alone is beyond the
+	// limit -- zipping several gigabytes only to throw the result away is slow and
+	// needs the same amount of disk space once more.
+	if size, err := fileSize(filepath.Join(dir, "simulation.log")); err != nil || size <= maxSize {
+		if err := zipDir(dir, dst); err != nil {
+			return false, err
+		}
+		if size, err := fileSize(dst); err != nil || size <= maxSize {
+			return false, err
+		}
+	}
+
+	if err := zipDirFiltered(dir, dst, func(name string) bool { return name != "simulation.log" }); err != nil {
+		return true, err
+	}
+	if size, err := fileSize(dst); err != nil {
+		return true, err
+	} else if size > maxSize {
+		_ = os.Remove(dst)
+		return true, errArtifactTooLarge
+	}
+	return true, nil
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func formatSize(bytes int64) string {
+	if bytes >= 1024*megabyte {
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1024*megabyte))
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/megabyte)
+}
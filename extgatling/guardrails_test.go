@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_zipReport_keeps_a_small_report_complete(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "simulation.log"), "run")
+	writeFile(t, filepath.Join(dir, "index.html"), "<html/>")
+	archive := filepath.Join(t.TempDir(), "report.zip")
+
+	trimmed, err := zipReport(dir, archive, 1)
+
+	require.NoError(t, err)
+	assert.False(t, trimmed)
+	assert.Equal(t, map[string]string{"simulation.log": "run", "index.html": "<html/>"}, readArchive(t, archive))
+}
+
+func Test_zipReport_drops_an_oversized_simulation_log(t *testing.T) {
+	dir := t.TempDir()
+	writeRandomFile(t, filepath.Join(dir, "simulation.log"), 2*megabyte)
+	writeFile(t, filepath.Join(dir, "index.html"), "<html/>")
+	archive := filepath.Join(t.TempDir(), "report.zip")
+
+	trimmed, err := zipReport(dir, archive, 1)
+
+	require.NoError(t, err)
+	assert.True(t, trimmed)
+	assert.Equal(t, map[string]string{"index.html": "<html/>"}, readArchive(t, archive))
+}
+
+func Test_zipReport_gives_up_if_the_trimmed_report_is_still_too_large(t *testing.T) {
+	dir := t.TempDir()
+	writeRandomFile(t, filepath.Join(dir, "simulation.log"), 2*megabyte)
+	writeRandomFile(t, filepath.Join(dir, "js", "huge.js"), 2*megabyte)
+	archive := filepath.Join(t.TempDir(), "report.zip")
+
+	_, err := zipReport(dir, archive, 1)
+
+	require.ErrorIs(t, err, errArtifactTooLarge)
+	assert.NoFileExists(t, archive)
+}
+
+func Test_dirSize_sums_up_nested_files(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "simulation.log"), "12345")
+	writeFile(t, filepath.Join(dir, "js", "app.js"), "123")
+
+	size, err := dirSize(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+}
+
+// writeRandomFile writes size bytes of random, thus incompressible, data.
+func writeRandomFile(t *testing.T, path string, size int) {
+	t.Helper()
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, content, 0644))
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"math"
+	"math/bits"
+)
+
+// histogramExact is the response time in milliseconds up to which a
+// responseTimeHistogram counts exactly. Larger ones share buckets of at most
+// 1/1024 of their value.
+const histogramExact = 2048
+
+// responseTimeHistogram counts response times in log-linear buckets, so its
+// memory is bounded by the largest response time instead of growing with the
+// number of requests of long runs.
+type responseTimeHistogram struct {
+	counts []int64
+	total  int64
+}
+
+func (h *responseTimeHistogram) add(responseTime int64) {
+	index := histogramIndex(max(responseTime, 0))
+	if index >= len(h.counts) {
+		h.counts = append(h.counts, make([]int64, index+1-len(h.counts))...)
+	}
+	h.counts[index]++
+	h.total++
+}
+
+// percentile returns the nearest-rank percentile, NaN if there are no
+// response times.
+func (h *responseTimeHistogram) percentile(p float64) float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+	rank := max(1, min(int64(math.Ceil(p/100*float64(h.total))), h.total))
+	var seen int64
+	for index, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			return float64(histogramValue(index))
+		}
+	}
+	return float64(histogramValue(len(h.counts) - 1))
+}
+
+func histogramIndex(value int64) int {
+	if value < histogramExact {
+		return int(value)
+	}
+	// value >> shift is within [histogramExact/2, histogramExact)
+	shift := bits.Len64(uint64(value)) - bits.Len64(histogramExact-1)
+	return shift*histogramExact/2 + int(value>>shift)
+}
+
+// histogramValue returns the smallest response time of the bucket at index.
+func histogramValue(index int) int64 {
+	if index < histogramExact {
+		return int64(index)
+	}
+	shift := index/(histogramExact/2) - 1
+	return int64(index-shift*histogramExact/2) << shift
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_responseTimeHistogram_percentiles(t *testing.T) {
+	var h responseTimeHistogram
+	assert.True(t, math.IsNaN(h.percentile(95)))
+
+	for responseTime := int64(1); responseTime <= 100; responseTime++ {
+		h.add(responseTime)
+	}
+
+	assert.Equal(t, 50.0, h.percentile(50))
+	assert.Equal(t, 95.0, h.percentile(95))
+	assert.Equal(t, 100.0, h.percentile(100))
+	assert.Equal(t, 1.0, h.percentile(0))
+}
+
+func Test_responseTimeHistogram_bounds_large_response_times(t *testing.T) {
+	var h responseTimeHistogram
+	h.add(60000)
+	h.add(math.MaxInt32)
+
+	assert.InDelta(t, 60000, h.percentile(50), 60000.0/1024)
+	assert.InDelta(t, math.MaxInt32, h.percentile(100), math.MaxInt32/1024)
+	assert.Less(t, len(h.counts), 30000)
+}
+
+func Test_histogramIndex_is_continuous(t *testing.T) {
+	previous := histogramIndex(histogramExact - 1)
+	for value := int64(histogramExact); value < 1<<20; value++ {
+		index := histogramIndex(value)
+		if index-previous > 1 || histogramValue(index) > value {
+			t.Fatalf("bucket %d of %d follows %d and starts at %d", index, value, previous, histogramValue(index))
+		}
+		previous = index
+	}
+}
@@ -0,0 +1,355 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// procSelfCgroup tells the cgroup of the extension, relative to cgroupRoot.
+var procSelfCgroup = "/proc/self/cgroup"
+
+const (
+	// extensionCgroup is the leaf group the extension moves itself into, as
+	// cgroup v2 allows no processes in a group limiting its children.
+	extensionCgroup = "steadybit-extension"
+	// cpuPeriod is the cgroup CPU period in microseconds the quota refers to.
+	cpuPeriod = 100000
+)
+
+var heapPattern = regexp.MustCompile(`^[1-9][0-9]*[kKmMgG]?$`)
+
+var outOfMemoryPattern = regexp.MustCompile(`java\.lang\.OutOfMemoryError(: [^\r\n]*)?`)
+
+// jvmLimits are the resources the Gatling JVM of a local run may use.
+type jvmLimits struct {
+	Heap          string   `json:"heap,omitempty"`
+	Options       []string `json:"options,omitempty"`
+	MemoryLimitMb int64    `json:"memoryLimitMb,omitempty"`
+	CpuLimit      float64  `json:"cpuLimit,omitempty"`
+	// the cgroup v2 child group enforcing the limits, empty if none
+	Cgroup string `json:"cgroup,omitempty"`
+}
+
+// parseJvmLimits returns the limits of the configuration, overridden by the
+// parameters of the run, nil if there are none.
+func parseJvmLimits(params GatlingLoadTestRunConfig) (*jvmLimits, error) {
+	limits := jvmLimits{
+		Heap:          config.Config.JvmHeap,
+		Options:       strings.Fields(config.Config.JvmOptions),
+		MemoryLimitMb: config.Config.RunMemoryLimitMb,
+		CpuLimit:      config.Config.RunCpuLimit,
+	}
+	if params.JvmHeap != "" {
+		limits.Heap = params.JvmHeap
+	}
+	if params.JvmOptions != "" {
+		limits.Options = strings.Fields(params.JvmOptions)
+	}
+	if params.MemoryLimit != nil && *params.MemoryLimit > 0 {
+		limits.MemoryLimitMb = int64(*params.MemoryLimit)
+	}
+	if params.CpuLimit != "" {
+		cpus, err := strconv.ParseFloat(params.CpuLimit, 64)
+		if err != nil || cpus <= 0 {
+			return nil, fmt.Errorf("invalid CPU limit %q, expected cores like 2 or 0.5", params.CpuLimit)
+		}
+		limits.CpuLimit = cpus
+	}
+	if limits.Heap != "" && !heapPattern.MatchString(limits.Heap) {
+		return nil, fmt.Errorf("invalid heap size %q, expected a size like 512m or 2g", limits.Heap)
+	}
+	for _, option := range limits.Options {
+		if !strings.HasPrefix(option, "-") {
+			return nil, fmt.Errorf("invalid JVM option %q, options start with -", option)
+		}
+	}
+	if limits.MemoryLimitMb < 0 || limits.CpuLimit < 0 {
+		return nil, errors.New("limits must not be negative")
+	}
+	if limits.Heap == "" && len(limits.Options) == 0 && limits.MemoryLimitMb == 0 && limits.CpuLimit == 0 {
+		return nil, nil
+	}
+	return &limits, nil
+}
+
+// jvmArgs returns the arguments of the Gatling JVM. Without a cgroup
+// enforcing the limits, the JVM is at least told to stay within them.
+func (l *jvmLimits) jvmArgs() []string {
+	var args []string
+	if l.Heap != "" {
+		args = append(args, "-Xmx"+l.Heap)
+	}
+	if l.Heap != "" || l.MemoryLimitMb > 0 {
+		// fail fast instead of going on with a JVM busy collecting garbage
+		args = append(args, "-XX:+ExitOnOutOfMemoryError")
+	}
+	if l.Cgroup == "" {
+		if l.MemoryLimitMb > 0 {
+			args = append(args, fmt.Sprintf("-XX:MaxRAM=%dm", l.MemoryLimitMb))
+		}
+		if l.CpuLimit > 0 {
+			args = append(args, fmt.Sprintf("-XX:ActiveProcessorCount=%d", int(math.Ceil(l.CpuLimit))))
+		}
+	}
+	return append(args, l.Options...)
+}
+
+// apply creates the cgroup for the memory and CPU limits, falling back to
+// the JVM's own limits if that fails, and adds the JVM arguments to the pom
+// of the run.
+func (l *jvmLimits) apply(executionRoot, name string) []action_kit_api.Message {
+	var messages []action_kit_api.Message
+	if l.MemoryLimitMb > 0 || l.CpuLimit > 0 {
+		group, err := createRunCgroup(cgroupRoot, procSelfCgroup, name, l)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to create a cgroup for the run")
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("The memory and CPU limits are not enforced, only the JVM is told about them: %s", err),
+			})
+		}
+		l.Cgroup = group
+	}
+	pom := filepath.Join(executionRoot, "gatling-maven-scaffold", "pom.xml")
+	if err := addJvmArgs(pom, l.jvmArgs()); err != nil {
+		log.Warn().Err(err).Msg("Failed to add the JVM arguments")
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("The JVM arguments are not applied: %s", err),
+		})
+	}
+	return messages
+}
+
+// addJvmArgs adds args to the jvmArgs of the gatling-maven-plugin in pom.
+func addJvmArgs(pom string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	content, err := os.ReadFile(pom)
+	if err != nil {
+		return err
+	}
+	plugin := bytes.Index(content, []byte("<artifactId>gatling-maven-plugin</artifactId>"))
+	if plugin < 0 {
+		return errors.New("the pom has no gatling-maven-plugin")
+	}
+	end := bytes.Index(content[plugin:], []byte("</jvmArgs>"))
+	if end < 0 {
+		return errors.New("the gatling-maven-plugin has no jvmArgs")
+	}
+	end += plugin
+	var inserted bytes.Buffer
+	for _, arg := range args {
+		inserted.WriteString("\t<jvmArg>")
+		if err := xml.EscapeText(&inserted, []byte(arg)); err != nil {
+			return err
+		}
+		inserted.WriteString("</jvmArg>\n\t\t\t\t\t")
+	}
+	return os.WriteFile(pom, slices.Concat(content[:end], inserted.Bytes(), content[end:]), 0644)
+}
+
+// PrepareRunCgroups enables the memory and cpu controllers for the groups of
+// the runs below the cgroup of the extension. As cgroup v2 allows no
+// processes in a group limiting its children, it moves the processes of the
+// extension into a leaf group first. Called once at startup, so that only
+// happens once and not as a side effect of a run, and only if the
+// configuration sets a memory or CPU limit for the runs.
+func PrepareRunCgroups() {
+	if config.Config.RunMemoryLimitMb <= 0 && config.Config.RunCpuLimit <= 0 {
+		log.Debug().Msg("No memory or CPU limit configured for the runs, leaving the cgroups alone")
+		return
+	}
+	if err := prepareRunCgroups(cgroupRoot, procSelfCgroup); err != nil {
+		log.Warn().Msgf("Memory and CPU limits of runs are not enforced by cgroups, only the JVM is told about them: %s", err)
+	}
+}
+
+func prepareRunCgroups(root, selfCgroup string) error {
+	own, err := ownCgroup(selfCgroup)
+	if err != nil {
+		return err
+	}
+	base := filepath.Join(root, own)
+	available, err := os.ReadFile(filepath.Join(base, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("no cgroup v2 at %s: %w", base, err)
+	}
+	var controllers []string
+	for _, controller := range []string{"memory", "cpu"} {
+		if slices.Contains(strings.Fields(string(available)), controller) {
+			controllers = append(controllers, controller)
+		}
+	}
+	if len(controllers) == 0 {
+		return fmt.Errorf("neither the memory nor the cpu controller is available in %s", base)
+	}
+	procs, err := os.ReadFile(filepath.Join(base, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	if len(strings.Fields(string(procs))) > 0 {
+		leaf := filepath.Join(base, extensionCgroup)
+		log.Info().Msgf("Moving the processes of the extension from %s to %s, to enforce memory and CPU limits of runs in cgroups", base, leaf)
+		if err := moveProcesses(base, leaf); err != nil {
+			return err
+		}
+	}
+	return enableControllers(base, controllers)
+}
+
+// createRunCgroup creates a cgroup v2 group below the one of the extension
+// with the memory and CPU limits of the run. Its controllers are enabled by
+// PrepareRunCgroups at startup.
+func createRunCgroup(root, selfCgroup, name string, limits *jvmLimits) (string, error) {
+	own, err := ownCgroup(selfCgroup)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(root, own)
+	enabled, err := os.ReadFile(filepath.Join(base, "cgroup.subtree_control"))
+	if err != nil {
+		return "", fmt.Errorf("no cgroup v2 at %s: %w", base, err)
+	}
+	var controllers []string
+	if limits.MemoryLimitMb > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if limits.CpuLimit > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	for _, controller := range controllers {
+		if !slices.Contains(strings.Fields(string(enabled)), controller) {
+			return "", fmt.Errorf("the %s controller is not enabled in %s", controller, base)
+		}
+	}
+
+	group := filepath.Join(base, name)
+	if err := os.Mkdir(group, 0755); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	if limits.MemoryLimitMb > 0 {
+		if err := os.WriteFile(filepath.Join(group, "memory.max"), []byte(strconv.FormatInt(limits.MemoryLimitMb*megabyte, 10)), 0644); err != nil {
+			removeRunCgroup(group)
+			return "", err
+		}
+		// without swap, exceeding the limit is an out of memory kill instead of a slow run
+		_ = os.WriteFile(filepath.Join(group, "memory.swap.max"), []byte("0"), 0644)
+	}
+	if limits.CpuLimit > 0 {
+		quota := fmt.Sprintf("%d %d", int64(limits.CpuLimit*cpuPeriod), cpuPeriod)
+		if err := os.WriteFile(filepath.Join(group, "cpu.max"), []byte(quota), 0644); err != nil {
+			removeRunCgroup(group)
+			return "", err
+		}
+	}
+	return group, nil
+}
+
+// ownCgroup returns the cgroup v2 path of the extension.
+func ownCgroup(selfCgroup string) (string, error) {
+	content, err := os.ReadFile(selfCgroup)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if path, found := strings.CutPrefix(line, "0::"); found {
+			return path, nil
+		}
+	}
+	return "", errors.New("cgroup v2 is required to enforce limits")
+}
+
+func enableControllers(group string, controllers []string) error {
+	enabled := "+" + strings.Join(controllers, " +")
+	return os.WriteFile(filepath.Join(group, "cgroup.subtree_control"), []byte(enabled), 0644)
+}
+
+// moveProcesses moves the processes of group into the leaf group.
+func moveProcesses(group, leaf string) error {
+	if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	procs, err := os.ReadFile(filepath.Join(group, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	for _, pid := range strings.Fields(string(procs)) {
+		if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), []byte(pid), 0644); err != nil {
+			return fmt.Errorf("failed to move process %s to %s: %w", pid, leaf, err)
+		}
+	}
+	return nil
+}
+
+// oomKills returns how often the kernel killed a process of the group for
+// exceeding its memory limit.
+func oomKills(group string) int64 {
+	content, err := os.ReadFile(filepath.Join(group, "memory.events"))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if value, found := strings.CutPrefix(line, "oom_kill "); found {
+			kills, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			return kills
+		}
+	}
+	return 0
+}
+
+// removeRunCgroup removes the group once its processes are gone.
+func removeRunCgroup(group string) {
+	for range 10 {
+		if err := os.Remove(group); err == nil || os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Warn().Msgf("Failed to remove the cgroup %s", group)
+}
+
+// detectOutOfMemory remembers in the state if Gatling ran out of heap, as
+// told by its output, or was killed for exceeding the memory limit.
+func detectOutOfMemory(state *GatlingLoadTestRunState, lines []string) {
+	if state.OutOfMemory != "" {
+		return
+	}
+	for _, line := range lines {
+		if match := outOfMemoryPattern.FindString(line); match != "" {
+			state.OutOfMemory = match
+			return
+		}
+	}
+	if state.Limits != nil && state.Limits.Cgroup != "" && oomKills(state.Limits.Cgroup) > 0 {
+		state.OutOfMemory = fmt.Sprintf("killed for exceeding the memory limit of %d MB", state.Limits.MemoryLimitMb)
+	}
+}
+
+// outOfMemoryError is the error of a run that ran out of memory.
+func outOfMemoryError(reason string) *action_kit_api.ActionKitError {
+	return &action_kit_api.ActionKitError{
+		Status: extutil.Ptr(action_kit_api.Errored),
+		Title:  fmt.Sprintf("Gatling ran out of memory (%s). Increase the heap size or the memory limit of the run.", reason),
+	}
+}
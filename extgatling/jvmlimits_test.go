@@ -0,0 +1,162 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseJvmLimits(t *testing.T) {
+	config.Config.JvmHeap = "1g"
+	config.Config.RunCpuLimit = 2
+	defer func() {
+		config.Config.JvmHeap = ""
+		config.Config.RunCpuLimit = 0
+	}()
+
+	limits, err := parseJvmLimits(GatlingLoadTestRunConfig{JvmOptions: "-XX:+UseZGC  -XX:+ZGenerational", MemoryLimit: new(2048)})
+	require.NoError(t, err)
+	assert.Equal(t, &jvmLimits{Heap: "1g", Options: []string{"-XX:+UseZGC", "-XX:+ZGenerational"}, MemoryLimitMb: 2048, CpuLimit: 2}, limits)
+
+	limits, err = parseJvmLimits(GatlingLoadTestRunConfig{JvmHeap: "512m", CpuLimit: "0.5"})
+	require.NoError(t, err)
+	assert.Equal(t, "512m", limits.Heap)
+	assert.Equal(t, 0.5, limits.CpuLimit)
+
+	_, err = parseJvmLimits(GatlingLoadTestRunConfig{JvmHeap: "lots"})
+	assert.Error(t, err)
+	_, err = parseJvmLimits(GatlingLoadTestRunConfig{JvmOptions: "UseZGC"})
+	assert.Error(t, err)
+	_, err = parseJvmLimits(GatlingLoadTestRunConfig{CpuLimit: "-1"})
+	assert.Error(t, err)
+}
+
+func Test_parseJvmLimits_without_limits(t *testing.T) {
+	limits, err := parseJvmLimits(GatlingLoadTestRunConfig{})
+
+	require.NoError(t, err)
+	assert.Nil(t, limits)
+}
+
+func Test_jvmArgs_tell_the_jvm_the_limits_without_cgroup(t *testing.T) {
+	limits := &jvmLimits{Heap: "1g", Options: []string{"-XX:+UseZGC"}, MemoryLimitMb: 2048, CpuLimit: 1.5}
+	assert.Equal(t, []string{"-Xmx1g", "-XX:+ExitOnOutOfMemoryError", "-XX:MaxRAM=2048m", "-XX:ActiveProcessorCount=2", "-XX:+UseZGC"}, limits.jvmArgs())
+
+	limits.Cgroup = "/sys/fs/cgroup/steadybit-gatling-1"
+	assert.Equal(t, []string{"-Xmx1g", "-XX:+ExitOnOutOfMemoryError", "-XX:+UseZGC"}, limits.jvmArgs())
+}
+
+func Test_addJvmArgs_to_the_gatling_plugin(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("..", scaffoldPom))
+	require.NoError(t, err)
+	pom := filepath.Join(t.TempDir(), "pom.xml")
+	require.NoError(t, os.WriteFile(pom, content, 0644))
+
+	require.NoError(t, addJvmArgs(pom, []string{"-Xmx1g", "-Dtag=<a&b>"}))
+
+	updated, err := os.ReadFile(pom)
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "<jvmArg>-Dsteadybit.agent.disable-jvm-attachment</jvmArg>\n\t\t\t\t\t\t<jvmArg>-Xmx1g</jvmArg>\n\t\t\t\t\t\t<jvmArg>-Dtag=&lt;a&amp;b&gt;</jvmArg>\n\t\t\t\t\t</jvmArgs>")
+	assert.Equal(t, 1, strings.Count(string(updated), "-Xmx1g"))
+}
+
+func Test_prepareRunCgroups_moves_the_extension_into_a_leaf_group(t *testing.T) {
+	root := t.TempDir()
+	selfCgroup := filepath.Join(t.TempDir(), "cgroup")
+	writeFile(t, selfCgroup, "0::/kubepods/pod-1\n")
+	base := filepath.Join(root, "kubepods", "pod-1")
+	writeFile(t, filepath.Join(base, "cgroup.controllers"), "cpuset cpu io memory pids")
+	writeFile(t, filepath.Join(base, "cgroup.procs"), "42\n")
+
+	require.NoError(t, prepareRunCgroups(root, selfCgroup))
+
+	assertFileContent(t, filepath.Join(base, extensionCgroup, "cgroup.procs"), "42")
+	assertFileContent(t, filepath.Join(base, "cgroup.subtree_control"), "+memory +cpu")
+}
+
+func Test_PrepareRunCgroups_only_with_configured_limits(t *testing.T) {
+	previousRoot, previousSelf := cgroupRoot, procSelfCgroup
+	defer func() { cgroupRoot, procSelfCgroup = previousRoot, previousSelf }()
+	cgroupRoot = t.TempDir()
+	procSelfCgroup = filepath.Join(t.TempDir(), "cgroup")
+	writeFile(t, procSelfCgroup, "0::/\n")
+	writeFile(t, filepath.Join(cgroupRoot, "cgroup.controllers"), "cpu memory")
+	writeFile(t, filepath.Join(cgroupRoot, "cgroup.procs"), "42\n")
+
+	PrepareRunCgroups()
+	assert.NoDirExists(t, filepath.Join(cgroupRoot, extensionCgroup))
+
+	config.Config.RunMemoryLimitMb = 512
+	defer func() { config.Config.RunMemoryLimitMb = 0 }()
+	PrepareRunCgroups()
+	assertFileContent(t, filepath.Join(cgroupRoot, extensionCgroup, "cgroup.procs"), "42")
+}
+
+func Test_prepareRunCgroups_without_controllers(t *testing.T) {
+	root := t.TempDir()
+	selfCgroup := filepath.Join(t.TempDir(), "cgroup")
+	writeFile(t, selfCgroup, "0::/\n")
+	writeFile(t, filepath.Join(root, "cgroup.controllers"), "pids")
+
+	assert.ErrorContains(t, prepareRunCgroups(root, selfCgroup), "neither the memory nor the cpu controller")
+}
+
+func Test_createRunCgroup(t *testing.T) {
+	root := t.TempDir()
+	selfCgroup := filepath.Join(t.TempDir(), "cgroup")
+	writeFile(t, selfCgroup, "0::/kubepods/pod-1\n")
+	writeFile(t, filepath.Join(root, "kubepods", "pod-1", "cgroup.subtree_control"), "cpu memory\n")
+
+	group, err := createRunCgroup(root, selfCgroup, "steadybit-gatling-1", &jvmLimits{MemoryLimitMb: 512, CpuLimit: 1.5})
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "kubepods", "pod-1", "steadybit-gatling-1"), group)
+	assertFileContent(t, filepath.Join(group, "memory.max"), "536870912")
+	assertFileContent(t, filepath.Join(group, "memory.swap.max"), "0")
+	assertFileContent(t, filepath.Join(group, "cpu.max"), "150000 100000")
+
+	writeFile(t, filepath.Join(root, "kubepods", "pod-1", "cgroup.subtree_control"), "cpu\n")
+	_, err = createRunCgroup(root, selfCgroup, "steadybit-gatling-2", &jvmLimits{MemoryLimitMb: 512})
+	assert.ErrorContains(t, err, "memory controller is not enabled")
+}
+
+func Test_createRunCgroup_requires_cgroup_v2(t *testing.T) {
+	selfCgroup := filepath.Join(t.TempDir(), "cgroup")
+	writeFile(t, selfCgroup, "12:memory:/docker/1\n11:cpu,cpuacct:/docker/1\n")
+
+	_, err := createRunCgroup(t.TempDir(), selfCgroup, "steadybit-gatling-1", &jvmLimits{MemoryLimitMb: 512})
+
+	assert.ErrorContains(t, err, "cgroup v2")
+}
+
+func Test_detectOutOfMemory(t *testing.T) {
+	state := &GatlingLoadTestRunState{}
+	detectOutOfMemory(state, []string{"[INFO] Simulation started\n"})
+	assert.Empty(t, state.OutOfMemory)
+
+	detectOutOfMemory(state, []string{"Terminating due to java.lang.OutOfMemoryError: Java heap space\n"})
+	assert.Equal(t, "java.lang.OutOfMemoryError: Java heap space", state.OutOfMemory)
+
+	group := t.TempDir()
+	writeFile(t, filepath.Join(group, "memory.events"), "low 0\nhigh 0\nmax 12\noom 1\noom_kill 1\n")
+	state = &GatlingLoadTestRunState{Limits: &jvmLimits{MemoryLimitMb: 512, Cgroup: group}}
+	detectOutOfMemory(state, nil)
+	assert.Equal(t, "killed for exceeding the memory limit of 512 MB", state.OutOfMemory)
+	assert.Contains(t, outOfMemoryError(state.OutOfMemory).Title, "512 MB")
+}
+
+func assertFileContent(t *testing.T, file, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
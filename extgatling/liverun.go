@@ -0,0 +1,425 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extmetrics"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	// liveWindow is the window the live statistics are computed over.
+	liveWindow = 10 * time.Second
+	// liveHistory is how long the per second samples of a run are kept.
+	liveHistory = 5 * time.Minute
+
+	// liveSourceGraphite receives the live statistics from Gatling's graphite
+	// data writer, liveSourceSimulationLog reads them from the simulation.log.
+	liveSourceGraphite      = "graphite"
+	liveSourceSimulationLog = "simulation-log"
+)
+
+// liveReadInterval is how often the simulation.log of a running simulation is
+// read. A var so tests don't have to wait.
+var liveReadInterval = time.Second
+
+// liveRuns holds the runs of the local action that are still going, keyed by
+// the action execution id. action_kit_sdk keeps no state in between calls, so
+// the background readers are tracked here, like extcmd does for the commands.
+var (
+	liveRuns   = map[uuid.UUID]*liveRun{}
+	liveRunsMu sync.Mutex
+)
+
+// liveRun collects the live statistics of a running Gatling, either received
+// from its graphite data writer or read from the simulation.log files it
+// writes, one for each simulation of the run.
+type liveRun struct {
+	ExecutionId           uuid.UUID
+	ExperimentKey         string
+	ExperimentExecutionId int
+
+	reportFolder string
+	started      time.Time
+	stop         chan struct{}
+	done         chan struct{}
+
+	// receives the statistics, nil when following the simulation.log
+	graphite *graphiteReceiver
+
+	// only touched by the goroutine following the simulation.log, or under mu
+	// when receiving from graphite
+	simulationStarted bool
+	requestCompleted  bool
+
+	mu          sync.Mutex
+	simulations map[string]*liveSimulation
+	// simulation names by report folder
+	reports map[string]string
+}
+
+// liveSimulation are the live statistics of one simulation.
+type liveSimulation struct {
+	Name        string
+	Ok          int64
+	Ko          int64
+	ActiveUsers int64
+	// samples per second of the request end time, oldest first
+	samples []liveSample
+}
+
+// liveSample is what Gatling recorded within one second.
+type liveSample struct {
+	Second        int64
+	Ok            int64
+	Ko            int64
+	ResponseTimes []int64
+	// percentiles reported by Gatling's graphite data writer, which sends no
+	// single response times
+	Reported *reportedPercentiles
+}
+
+// reportedPercentiles are response time percentiles in milliseconds.
+type reportedPercentiles struct {
+	P50 float64
+	P95 float64
+	P99 float64
+}
+
+// liveStats are statistics of a simulation over a time window, plus the totals
+// since its start. Response times are in milliseconds and NaN if there were no
+// requests.
+type liveStats struct {
+	TotalOk           int64
+	TotalKo           int64
+	Requests          int64
+	Ko                int64
+	RequestsPerSecond float64
+	ErrorRatio        float64
+	P50               float64
+	P95               float64
+	P99               float64
+	ActiveUsers       int64
+}
+
+// startLiveRun starts collecting the live statistics of a run: with the
+// graphite source it starts the receiver Gatling has to be configured to write
+// to, otherwise it follows the reports Gatling writes to reportFolder.
+func startLiveRun(state *GatlingLoadTestRunState, reportFolder string) (*liveRun, error) {
+	run := &liveRun{
+		ExecutionId:           state.ExecutionId,
+		ExperimentKey:         state.ExperimentKey,
+		ExperimentExecutionId: state.ExperimentExecutionId,
+		reportFolder:          reportFolder,
+		started:               time.Now(),
+		stop:                  make(chan struct{}),
+		done:                  make(chan struct{}),
+		simulations:           map[string]*liveSimulation{},
+		reports:               map[string]string{},
+		// a run compiled in Prepare recorded its compile duration there
+		simulationStarted: state.Sync != nil,
+	}
+	if config.Config.LiveMetricsSource != liveSourceSimulationLog {
+		graphite, err := listenGraphite(run)
+		if err != nil {
+			return nil, err
+		}
+		run.graphite = graphite
+	}
+	liveRunsMu.Lock()
+	liveRuns[state.ExecutionId] = run
+	liveRunsMu.Unlock()
+	go run.follow()
+	return run, nil
+}
+
+// stopLiveRun stops following the reports of an execution and forgets its
+// live statistics. Returns the run with its final statistics, nil if it wasn't
+// followed.
+func stopLiveRun(executionId uuid.UUID) *liveRun {
+	liveRunsMu.Lock()
+	run, ok := liveRuns[executionId]
+	delete(liveRuns, executionId)
+	liveRunsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	close(run.stop)
+	<-run.done
+	return run
+}
+
+// getLiveRun returns the run of an execution, nil if it isn't followed.
+func getLiveRun(executionId uuid.UUID) *liveRun {
+	liveRunsMu.Lock()
+	defer liveRunsMu.Unlock()
+	return liveRuns[executionId]
+}
+
+// getLiveRuns returns the runs that are currently followed.
+func getLiveRuns() []*liveRun {
+	liveRunsMu.Lock()
+	defer liveRunsMu.Unlock()
+	runs := make([]*liveRun, 0, len(liveRuns))
+	for _, run := range liveRuns {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+func (r *liveRun) follow() {
+	defer close(r.done)
+	if r.graphite != nil {
+		<-r.stop
+		r.graphite.Close()
+		return
+	}
+
+	readers := map[string]*simulationLogReader{}
+	defer func() {
+		for _, reader := range readers {
+			if reader != nil {
+				_ = reader.Close()
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(liveReadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.readReports(readers)
+		}
+	}
+}
+
+// readReports reads what got appended to the simulation.log files in the
+// report folder, opening the ones of simulations started since the last call.
+func (r *liveRun) readReports(readers map[string]*simulationLogReader) {
+	entries, err := os.ReadDir(r.reportFolder)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(r.reportFolder, entry.Name(), "simulation.log")
+		reader, ok := readers[path]
+		if !ok {
+			if reader, err = openSimulationLog(path); err != nil {
+				continue
+			}
+			readers[path] = reader
+		}
+		if reader == nil {
+			continue // failed before, don't log the same error every second
+		}
+		var simulation *liveSimulation
+		if err := reader.readAvailable(func(record any) {
+			if run, ok := record.(*simulationLogRun); ok {
+				simulation = r.simulation(run.Simulation)
+				r.mu.Lock()
+				r.reports[entry.Name()] = run.Simulation
+				r.mu.Unlock()
+				if !r.simulationStarted {
+					r.simulationStarted = true
+					extmetrics.ObserveCompileDuration(time.UnixMilli(run.Start).Sub(r.started))
+				}
+				return
+			}
+			if request, ok := record.(*requestRecord); ok && !r.requestCompleted {
+				r.requestCompleted = true
+				extmetrics.ObserveTimeToFirstRequest(time.UnixMilli(request.End).Sub(r.started))
+			}
+			if simulation == nil {
+				simulation = r.simulation(reader.run.Simulation)
+			}
+			r.record(simulation, record)
+		}); err != nil {
+			log.Warn().Err(err).Msgf("Failed to read %s, no live statistics for this simulation", path)
+			_ = reader.Close()
+			readers[path] = nil
+		}
+	}
+}
+
+func (r *liveRun) simulation(name string) *liveSimulation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	simulation, ok := r.simulations[name]
+	if !ok {
+		simulation = &liveSimulation{Name: name}
+		r.simulations[name] = simulation
+	}
+	return simulation
+}
+
+func (r *liveRun) record(simulation *liveSimulation, record any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch rec := record.(type) {
+	case *requestRecord:
+		sample := simulation.sample(rec.End / 1000)
+		if rec.Ok {
+			simulation.Ok++
+			sample.Ok++
+		} else {
+			simulation.Ko++
+			sample.Ko++
+		}
+		sample.ResponseTimes = append(sample.ResponseTimes, rec.End-rec.Start)
+	case *userRecord:
+		if rec.Start {
+			simulation.ActiveUsers++
+		} else {
+			simulation.ActiveUsers--
+		}
+	}
+}
+
+// sample returns the sample of the given second, adding it if needed. Records
+// arrive mostly, but not strictly, in order.
+func (s *liveSimulation) sample(second int64) *liveSample {
+	i := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Second >= second })
+	if i < len(s.samples) && s.samples[i].Second == second {
+		return &s.samples[i]
+	}
+	s.samples = append(s.samples, liveSample{})
+	copy(s.samples[i+1:], s.samples[i:])
+	s.samples[i] = liveSample{Second: second}
+
+	cutoff := second - int64(liveHistory/time.Second)
+	drop := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Second > cutoff })
+	if drop > 0 {
+		s.samples = append(s.samples[:0], s.samples[drop:]...)
+		i -= drop
+	}
+	return &s.samples[i]
+}
+
+// simulationOf returns the name of the simulation that wrote the report
+// folder, empty if its simulation.log wasn't read.
+func (r *liveRun) simulationOf(report string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reports[report]
+}
+
+// Simulations returns the live statistics of the run's simulations over the
+// window ending now.
+func (r *liveRun) Simulations(now time.Time, window time.Duration) map[string]liveStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[string]liveStats, len(r.simulations))
+	for name, simulation := range r.simulations {
+		stats[name] = simulation.stats(now, window)
+	}
+	return stats
+}
+
+func (s *liveSimulation) stats(now time.Time, window time.Duration) liveStats {
+	from := now.Add(-window).Unix()
+	to := now.Unix()
+	stats := liveStats{TotalOk: s.Ok, TotalKo: s.Ko, ActiveUsers: s.ActiveUsers}
+	var responseTimes []int64
+	// the reported percentiles of the seconds, weighted by their requests
+	var reported reportedPercentiles
+	var reportedRequests int64
+	for _, sample := range s.samples {
+		if sample.Second <= from || sample.Second > to {
+			continue
+		}
+		stats.Requests += sample.Ok + sample.Ko
+		stats.Ko += sample.Ko
+		responseTimes = append(responseTimes, sample.ResponseTimes...)
+		if sample.Reported != nil {
+			n := sample.Ok + sample.Ko
+			reported.P50 += sample.Reported.P50 * float64(n)
+			reported.P95 += sample.Reported.P95 * float64(n)
+			reported.P99 += sample.Reported.P99 * float64(n)
+			reportedRequests += n
+		}
+	}
+	stats.RequestsPerSecond = float64(stats.Requests) / window.Seconds()
+	if stats.Requests > 0 {
+		stats.ErrorRatio = float64(stats.Ko) / float64(stats.Requests)
+	}
+	sort.Slice(responseTimes, func(i, j int) bool { return responseTimes[i] < responseTimes[j] })
+	stats.P50 = percentile(responseTimes, 50)
+	stats.P95 = percentile(responseTimes, 95)
+	stats.P99 = percentile(responseTimes, 99)
+	if len(responseTimes) == 0 && reportedRequests > 0 {
+		// an approximation, percentiles of several seconds can't be combined exactly
+		stats.P50 = reported.P50 / float64(reportedRequests)
+		stats.P95 = reported.P95 / float64(reportedRequests)
+		stats.P99 = reported.P99 / float64(reportedRequests)
+	}
+	return stats
+}
+
+// reported returns the percentiles reported for the second, adding them if
+// needed.
+func (s *liveSample) reported() *reportedPercentiles {
+	if s.Reported == nil {
+		s.Reported = &reportedPercentiles{}
+	}
+	return s.Reported
+}
+
+// liveMessages summarize the live statistics of the run's simulations for the
+// status of the action.
+func liveMessages(run *liveRun, now time.Time) []action_kit_api.Message {
+	stats := run.Simulations(now, liveWindow)
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]action_kit_api.Message, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: liveLine(name, stats[name]),
+		})
+	}
+	return messages
+}
+
+// liveLine summarizes the live statistics of a simulation in one line.
+func liveLine(name string, s liveStats) string {
+	p95 := "-"
+	if !math.IsNaN(s.P95) {
+		p95 = fmt.Sprintf("%.0f ms", s.P95)
+	}
+	return fmt.Sprintf("%s: %d requests (%d KO), last %s: %.1f req/s, %.1f%% errors, p95 %s, %d active users",
+		name, s.TotalOk+s.TotalKo, s.TotalKo, liveWindow, s.RequestsPerSecond, s.ErrorRatio*100, p95, s.ActiveUsers)
+}
+
+// percentile returns the nearest-rank percentile of the sorted values, NaN if
+// there are none.
+func percentile(sorted []int64, p float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return float64(sorted[rank])
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_liveSimulation_stats_over_the_window(t *testing.T) {
+	now := time.Unix(1767268800, 0)
+	simulation := &liveSimulation{Ok: 97, Ko: 3, ActiveUsers: 4}
+	// outside of the window
+	simulation.sample(now.Unix() - 20).ResponseTimes = []int64{5000}
+	for i := int64(0); i < 10; i++ {
+		sample := simulation.sample(now.Unix() - i)
+		sample.Ok = 9
+		sample.Ko = 1
+		for rt := int64(1); rt <= 10; rt++ {
+			sample.ResponseTimes = append(sample.ResponseTimes, rt*10)
+		}
+	}
+
+	stats := simulation.stats(now, 10*time.Second)
+
+	assert.Equal(t, liveStats{
+		TotalOk:           97,
+		TotalKo:           3,
+		Requests:          100,
+		Ko:                10,
+		RequestsPerSecond: 10,
+		ErrorRatio:        0.1,
+		P50:               50,
+		P95:               100,
+		P99:               100,
+		ActiveUsers:       4,
+	}, stats)
+}
+
+func Test_liveSimulation_stats_without_requests(t *testing.T) {
+	stats := (&liveSimulation{}).stats(time.Now(), 10*time.Second)
+
+	assert.Zero(t, stats.Requests)
+	assert.True(t, math.IsNaN(stats.P95))
+}
+
+func Test_liveSimulation_sample_drops_old_samples(t *testing.T) {
+	simulation := &liveSimulation{}
+	simulation.sample(100)
+	simulation.sample(102)
+	simulation.sample(101)
+	simulation.sample(100 + int64(liveHistory/time.Second))
+
+	seconds := make([]int64, 0)
+	for _, sample := range simulation.samples {
+		seconds = append(seconds, sample.Second)
+	}
+	assert.Equal(t, []int64{101, 102, 100 + int64(liveHistory/time.Second)}, seconds)
+}
+
+func Test_liveRun_follows_the_simulation_log(t *testing.T) {
+	defer useLiveReadInterval(10 * time.Millisecond)()
+	reportFolder := t.TempDir()
+	config.Config.LiveMetricsSource = liveSourceSimulationLog
+	defer func() { config.Config.LiveMetricsSource = "" }()
+	state := &GatlingLoadTestRunState{ExecutionId: uuid.New(), ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+	_, err := startLiveRun(state, reportFolder)
+	require.NoError(t, err)
+	defer stopLiveRun(state.ExecutionId)
+
+	now := time.Now().UnixMilli()
+	content := newSimulationLogWriter("BasicSimulation", now-1000, "Users").
+		user(0, true, now-900).
+		request(nil, "home", now-800, now-700, true, "").
+		request(nil, "home", now-600, now-400, false, "boom").
+		bytes()
+	require.NoError(t, os.MkdirAll(filepath.Join(reportFolder, "basicsimulation-1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportFolder, "basicsimulation-1", "simulation.log"), content, 0644))
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewLiveMetricsCollector())
+	require.Eventually(t, func() bool {
+		families, err := registry.Gather()
+		return err == nil && len(families) == 5
+	}, 2*time.Second, 10*time.Millisecond)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	metrics := map[string][]*dto.Metric{}
+	for _, family := range families {
+		metrics[family.GetName()] = family.GetMetric()
+	}
+	assert.Equal(t, map[string]string{"experiment_key": "ADM-1", "execution_id": "42", "simulation": "BasicSimulation"}, labels(metrics["gatling_active_users"][0]))
+	assert.InDelta(t, 1, metrics["gatling_active_users"][0].GetGauge().GetValue(), 0.001)
+	assert.InDelta(t, 0.5, metrics["gatling_error_ratio"][0].GetGauge().GetValue(), 0.001)
+	require.Len(t, metrics["gatling_requests_total"], 2)
+	require.Len(t, metrics["gatling_response_time_seconds"], 3)
+
+	stopLiveRun(state.ExecutionId)
+	families, err = registry.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, families)
+}
+
+func labels(metric *dto.Metric) map[string]string {
+	result := map[string]string{}
+	for _, pair := range metric.GetLabel() {
+		result[pair.GetName()] = pair.GetValue()
+	}
+	return result
+}
+
+func useLiveReadInterval(interval time.Duration) func() {
+	previous := liveReadInterval
+	liveReadInterval = interval
+	return func() { liveReadInterval = previous }
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// cgroupRoot is where the cgroup filesystem of the extension's container is
+// mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// scaffoldPom is the pom of the maven project the simulations are run with.
+var scaffoldPom = "gatling-maven-scaffold/pom.xml"
+
+// unlimitedMemory is the value cgroup v1 reports if no memory limit is set,
+// rounded down to a page.
+const unlimitedMemory = int64(1) << 60
+
+// locationResources is what the location offers to run simulations with.
+type locationResources struct {
+	Cpus           float64
+	MemoryMb       int64
+	JavaVersion    string
+	GatlingVersion string
+}
+
+// currentLocationResources are detected once, they don't change while the
+// extension runs.
+var currentLocationResources = sync.OnceValue(func() locationResources {
+	resources := locationResources{
+		Cpus:           float64(runtime.NumCPU()),
+		JavaVersion:    javaVersion(),
+		GatlingVersion: gatlingVersion(scaffoldPom),
+	}
+	if cpus, limited := cgroupCpus(cgroupRoot); limited {
+		resources.Cpus = min(cpus, resources.Cpus)
+	}
+	if limit, limited := cgroupMemoryLimit(cgroupRoot); limited {
+		resources.MemoryMb = limit / megabyte
+	} else {
+		resources.MemoryMb = totalMemory("/proc/meminfo") / megabyte
+	}
+	return resources
+})
+
+// cgroupCpus returns the CPU cores the cgroup at root may use, false if it
+// isn't limited. Supports cgroup v2 and v1.
+func cgroupCpus(root string) (float64, bool) {
+	if content, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(content))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		return cpuQuota(fields[0], fields[1])
+	}
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return cpuQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func cpuQuota(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
+
+// cgroupMemoryLimit returns the memory limit in bytes of the cgroup at root,
+// false if it isn't limited. Supports cgroup v2 and v1.
+func cgroupMemoryLimit(root string) (int64, bool) {
+	content, err := os.ReadFile(filepath.Join(root, "memory.max"))
+	if err != nil {
+		content, err = os.ReadFile(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+		if err != nil {
+			return 0, false
+		}
+	}
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil || limit <= 0 || limit >= unlimitedMemory {
+		return 0, false
+	}
+	return limit, true
+}
+
+// totalMemory returns the memory of the host in bytes from meminfo, zero if
+// unknown.
+func totalMemory(meminfo string) int64 {
+	file, err := os.Open(meminfo)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, _ := strconv.ParseInt(fields[1], 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+var javaVersionPattern = regexp.MustCompile(`version "([^"]+)"`)
+
+// javaVersion returns the version of the java on the path, empty if there is
+// none.
+func javaVersion() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "java", "-version").CombinedOutput()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to determine the java version")
+		return ""
+	}
+	return parseJavaVersion(string(output))
+}
+
+func parseJavaVersion(output string) string {
+	if match := javaVersionPattern.FindStringSubmatch(output); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+var gatlingVersionPattern = regexp.MustCompile(`<gatling\.version>\s*([^<\s]+)\s*</gatling\.version>`)
+
+// gatlingVersion returns the Gatling version the scaffold pom runs
+// simulations with, empty if unknown.
+func gatlingVersion(pom string) string {
+	content, err := os.ReadFile(pom)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to determine the Gatling version")
+		return ""
+	}
+	if match := gatlingVersionPattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cgroupCpus(t *testing.T) {
+	v2 := t.TempDir()
+	writeFile(t, filepath.Join(v2, "cpu.max"), "150000 100000\n")
+	cpus, limited := cgroupCpus(v2)
+	assert.True(t, limited)
+	assert.Equal(t, 1.5, cpus)
+
+	unlimited := t.TempDir()
+	writeFile(t, filepath.Join(unlimited, "cpu.max"), "max 100000\n")
+	_, limited = cgroupCpus(unlimited)
+	assert.False(t, limited)
+
+	v1 := t.TempDir()
+	writeFile(t, filepath.Join(v1, "cpu", "cpu.cfs_quota_us"), "400000\n")
+	writeFile(t, filepath.Join(v1, "cpu", "cpu.cfs_period_us"), "100000\n")
+	cpus, limited = cgroupCpus(v1)
+	assert.True(t, limited)
+	assert.Equal(t, 4.0, cpus)
+
+	writeFile(t, filepath.Join(v1, "cpu", "cpu.cfs_quota_us"), "-1\n")
+	_, limited = cgroupCpus(v1)
+	assert.False(t, limited)
+}
+
+func Test_cgroupMemoryLimit(t *testing.T) {
+	v2 := t.TempDir()
+	writeFile(t, filepath.Join(v2, "memory.max"), "2147483648\n")
+	limit, limited := cgroupMemoryLimit(v2)
+	assert.True(t, limited)
+	assert.Equal(t, int64(2147483648), limit)
+
+	writeFile(t, filepath.Join(v2, "memory.max"), "max\n")
+	_, limited = cgroupMemoryLimit(v2)
+	assert.False(t, limited)
+
+	v1 := t.TempDir()
+	writeFile(t, filepath.Join(v1, "memory", "memory.limit_in_bytes"), "9223372036854771712\n")
+	_, limited = cgroupMemoryLimit(v1)
+	assert.False(t, limited)
+}
+
+func Test_totalMemory(t *testing.T) {
+	meminfo := filepath.Join(t.TempDir(), "meminfo")
+	writeFile(t, meminfo, "MemTotal:       16384000 kB\nMemFree:         1024000 kB\n")
+
+	assert.Equal(t, int64(16384000*1024), totalMemory(meminfo))
+}
+
+func Test_parseJavaVersion(t *testing.T) {
+	output := "openjdk version \"26\" 2026-03-17\nOpenJDK Runtime Environment Zulu26.28+85-CA (build 26+35)\n"
+
+	assert.Equal(t, "26", parseJavaVersion(output))
+	assert.Empty(t, parseJavaVersion("java: command not found"))
+}
+
+func Test_gatlingVersion(t *testing.T) {
+	assert.Equal(t, "3.15.1", gatlingVersion(filepath.Join("..", scaffoldPom)))
+	assert.Empty(t, gatlingVersion(filepath.Join(t.TempDir(), "pom.xml")))
+}
+
+func Test_selfLocation_reports_the_configured_location(t *testing.T) {
+	config.Config.LocationRegion = "eu-central-1"
+	config.Config.LocationZone = "eu-central-1a"
+	config.Config.LocationWeight = 2
+	config.Config.LocationLabels = map[string]string{"team": "checkout"}
+	defer func() {
+		config.Config.LocationRegion = ""
+		config.Config.LocationZone = ""
+		config.Config.LocationWeight = 0
+		config.Config.LocationLabels = nil
+	}()
+
+	attributes := selfLocation().Attributes
+
+	assert.Equal(t, []string{"eu-central-1"}, attributes["gatling.location.region"])
+	assert.Equal(t, []string{"eu-central-1a"}, attributes["gatling.location.zone"])
+	assert.Equal(t, []string{"2"}, attributes["gatling.location.weight"])
+	assert.Equal(t, []string{"checkout"}, attributes["gatling.location.label.team"])
+	assert.NotEmpty(t, attributes["gatling.location.cpus"])
+}
+
+func Test_selfLocation_reports_the_active_and_queued_runs(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	assert.Equal(t, []string{"0"}, selfLocation().Attributes["gatling.location.active-runs"])
+	assert.Equal(t, []string{"0"}, selfLocation().Attributes["gatling.location.queued-runs"])
+
+	reserveRunSlot(uuid.New(), true, time.Now())
+	assert.Equal(t, []string{"1"}, selfLocation().Attributes["gatling.location.active-runs"], "a run compiling in Prepare")
+
+	reserveRunSlot(uuid.New(), true, time.Now())
+	assert.Equal(t, []string{"1"}, selfLocation().Attributes["gatling.location.active-runs"])
+	assert.Equal(t, []string{"1"}, selfLocation().Attributes["gatling.location.queued-runs"])
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// Stages of a local run shown in the markdown widget.
+const (
+	stageQueued    = "queued"  // waiting for a free slot
+	stageWaiting   = "waiting" // waiting for the other locations of a synchronized start
+	stageCompiling = "compiling"
+	stageStarting  = "starting" // compiled in Prepare, waiting for Gatling
+	stageRunning   = "running"
+)
+
+// progressInterval is how often the markdown widget gets a progress line, it
+// appends them, so not on every status call.
+const progressInterval = 30 * time.Second
+
+func markdownMessage(text string) action_kit_api.Message {
+	return action_kit_api.Message{
+		Message: text,
+		Type:    new(markdownMessageType),
+	}
+}
+
+// progressMessages returns the lines to append to the markdown widget while
+// the run is going: when the simulations started and their live statistics
+// every progressInterval.
+func progressMessages(state *GatlingLoadTestRunState, run *liveRun, now time.Time) []action_kit_api.Message {
+	stats := run.Simulations(now, liveWindow)
+	if len(stats) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var messages []action_kit_api.Message
+	if state.Stage != stageRunning {
+		started := fmt.Sprintf("▶️ Simulation %s started", strings.Join(names, ", "))
+		if state.Stage == stageCompiling {
+			started += fmt.Sprintf(" after %s of compiling", now.Sub(run.started).Round(time.Second))
+		}
+		state.Stage = stageRunning
+		messages = append(messages, markdownMessage(started))
+		state.LastProgress = now.UnixMilli()
+		return messages
+	}
+	if now.UnixMilli()-state.LastProgress < progressInterval.Milliseconds() {
+		return nil
+	}
+	state.LastProgress = now.UnixMilli()
+	for _, name := range names {
+		messages = append(messages, markdownMessage(fmt.Sprintf("- %s %s", now.UTC().Format("15:04:05"), liveLine(name, stats[name]))))
+	}
+	return messages
+}
+
+// summaryMessages returns the final summary of the reports and the outcome of
+// their assertions for the markdown widget.
+func summaryMessages(reportDirs []string) []action_kit_api.Message {
+	var messages []action_kit_api.Message
+	if summaries := readSimulationSummaries(reportDirs); len(summaries) > 0 {
+		var table strings.Builder
+		table.WriteString("| Simulation | Requests | KO | Errors | p50 | p95 | p99 | Throughput |\n")
+		table.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|\n")
+		for _, summary := range summaries {
+			global := summary.Global
+			_, _ = fmt.Fprintf(&table, "| %s | %d | %d | %s%% | %s ms | %s ms | %s ms | %s req/s |\n",
+				markdownCell(summary.Simulation), global.Requests, global.Ko, formatNumber(global.ErrorPercentage),
+				formatNumber(global.P50), formatNumber(global.P95), formatNumber(global.P99), formatNumber(global.Throughput))
+		}
+		messages = append(messages, markdownMessage("### Summary"), markdownMessage(table.String()))
+	}
+
+	var assertions []action_kit_api.Message
+	for _, reportDir := range reportDirs {
+		results, err := readAssertions(reportDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Warn().Err(err).Msgf("Failed to read the assertions of report %s", filepath.Base(reportDir))
+			}
+			continue
+		}
+		for _, assertion := range results {
+			icon := "❌"
+			if assertion.Result {
+				icon = "✅"
+			}
+			values := make([]string, 0, len(assertion.ActualValue))
+			for _, value := range assertion.ActualValue {
+				values = append(values, formatNumber(value))
+			}
+			assertions = append(assertions, markdownMessage(fmt.Sprintf("- %s %s (%s)", icon, assertion.Message, strings.Join(values, ", "))))
+		}
+	}
+	if len(assertions) > 0 {
+		messages = append(messages, markdownMessage("### Assertions"))
+		messages = append(messages, assertions...)
+	}
+	return messages
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_progressMessages(t *testing.T) {
+	now := time.Unix(1767268800, 0)
+	run := &liveRun{started: now.Add(-42 * time.Second), simulations: map[string]*liveSimulation{}}
+	state := &GatlingLoadTestRunState{Stage: stageCompiling}
+
+	assert.Empty(t, progressMessages(state, run, now))
+
+	run.simulations["basicsimulation"] = &liveSimulation{Ok: 10, ActiveUsers: 2}
+	messages := progressMessages(state, run, now)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "▶️ Simulation basicsimulation started after 42s of compiling", messages[0].Message)
+	assert.Equal(t, markdownMessageType, *messages[0].Type)
+	assert.Equal(t, stageRunning, state.Stage)
+
+	assert.Empty(t, progressMessages(state, run, now.Add(5*time.Second)))
+
+	messages = progressMessages(state, run, now.Add(progressInterval))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "- 12:00:30 basicsimulation: 10 requests (0 KO), last 10s: 0.0 req/s, 0.0% errors, p95 -, 2 active users", messages[0].Message)
+}
+
+func Test_summaryMessages(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+	writeFile(t, filepath.Join(reportDir, "js", "assertions.json"), assertionsJson)
+
+	messages := summaryMessages([]string{reportDir})
+
+	texts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		assert.Equal(t, markdownMessageType, *message.Type)
+		texts = append(texts, message.Message)
+	}
+	assert.Equal(t, []string{
+		"### Summary",
+		"| Simulation | Requests | KO | Errors | p50 | p95 | p99 | Throughput |\n" +
+			"|---|---:|---:|---:|---:|---:|---:|---:|\n" +
+			"| basicsimulation | 200 | 10 | 5% | 40 ms | 300 ms | 700 ms | 20 req/s |\n",
+		"### Assertions",
+		"- ✅ Global: max of response time is less than 1000.0 (900)",
+		"- ❌ Global: percentage of failed events is less than 1.0 (5)",
+	}, texts)
+}
+
+func Test_summaryMessages_without_report(t *testing.T) {
+	assert.Empty(t, summaryMessages([]string{filepath.Join(t.TempDir(), "basicsimulation-1")}))
+}
+
+func Test_progressMessages_after_a_synchronized_start(t *testing.T) {
+	now := time.Unix(1767268800, 0)
+	run := &liveRun{started: now.Add(-3 * time.Second), simulations: map[string]*liveSimulation{"basicsimulation": {Ok: 1}}}
+	state := &GatlingLoadTestRunState{Stage: stageStarting}
+
+	messages := progressMessages(state, run, now)
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, "▶️ Simulation basicsimulation started", messages[0].Message)
+}
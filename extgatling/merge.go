@@ -0,0 +1,387 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/exttracing"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extcmd"
+	"github.com/steadybit/extension-kit/extconversion"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const mergeActionId = "com.steadybit.extension_gatling.merge"
+
+// GatlingMergeAction generates one report from the simulation.log files the
+// locations of an experiment execution pushed to this extension instance, the
+// way Gatling merges the results of several injectors.
+type GatlingMergeAction struct{}
+
+type GatlingMergeState struct {
+	ExecutionId           uuid.UUID `json:"executionId"`
+	ExperimentKey         string    `json:"experimentKey"`
+	ExperimentExecutionId int       `json:"experimentExecutionId"`
+	ArtifactMode          string    `json:"artifactMode"`
+	ExpectedLocations     int       `json:"expectedLocations,omitempty"`
+	Pid                   int       `json:"pid"`
+	CmdStateID            string    `json:"cmdStateId"`
+	// the folder Gatling generates the merged report in
+	ReportDir string `json:"reportDir,omitempty"`
+	// the pushed simulation.log files, removed once the merged report exists
+	PushedLogs []string `json:"pushedLogs,omitempty"`
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[GatlingMergeState]           = (*GatlingMergeAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[GatlingMergeState] = (*GatlingMergeAction)(nil)
+	_ action_kit_sdk.ActionWithStop[GatlingMergeState]   = (*GatlingMergeAction)(nil)
+)
+
+func NewGatlingMergeAction() action_kit_sdk.Action[GatlingMergeState] {
+	return &GatlingMergeAction{}
+}
+
+func (m *GatlingMergeAction) NewEmptyState() GatlingMergeState {
+	return GatlingMergeState{}
+}
+
+func (m *GatlingMergeAction) Describe() action_kit_api.ActionDescription {
+	description := action_kit_api.ActionDescription{
+		Id:          mergeActionId,
+		Label:       "Gatling Merge Reports",
+		Description: "Merge the results of a Gatling load test run by several locations into one report.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(actionIcon),
+		Technology:  new("Gatling"),
+		Kind:        action_kit_api.Other,
+		TimeControl: action_kit_api.TimeControlInternal,
+		Hint: &action_kit_api.ActionHint{
+			Content: "The locations push their simulation.log to the extension set in STEADYBIT_EXTENSION_AGGREGATION_URL when the load test ends, run the merge on that extension after the load test.",
+			Type:    action_kit_api.HintInfo,
+		},
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:        "locations",
+				Label:       "Expected Locations",
+				Description: new("How many locations ran the load test. If fewer pushed their results, the merged report is still generated, with a warning."),
+				Type:        action_kit_api.ActionParameterTypeInteger,
+				Required:    new(false),
+			},
+			{
+				Name:         "artifactMode",
+				Label:        "Attached Report",
+				Description:  new("What to attach to the experiment execution after the merge: the full HTML report, a summary of the statistics or nothing."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(artifactModeFull),
+				Required:     new(false),
+				Advanced:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Full report (zip)", Value: artifactModeFull},
+					action_kit_api.ExplicitParameterOption{Label: "Summary only (json)", Value: artifactModeSummary},
+					action_kit_api.ExplicitParameterOption{Label: "None", Value: artifactModeNone},
+				}),
+			},
+		},
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.MarkdownWidget{
+				Type:        action_kit_api.ComSteadybitWidgetMarkdown,
+				Title:       "Gatling",
+				MessageType: markdownMessageType,
+				Append:      true,
+			},
+		}),
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("2s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+
+	if config.Config.EnableLocationSelection {
+		description.Parameters = append(description.Parameters, action_kit_api.ActionParameter{
+			Name:  "-",
+			Label: "Filter Gatling Locations",
+			Type:  action_kit_api.ActionParameterTypeTargetSelection,
+			Order: new(3),
+		})
+		description.TargetSelection = new(action_kit_api.TargetSelection{
+			TargetType: targetType,
+			DefaultBlastRadius: new(action_kit_api.DefaultBlastRadius{
+				Mode:  action_kit_api.DefaultBlastRadiusModeMaximum,
+				Value: 1,
+			}),
+			MissingQuerySelection: extutil.Ptr(action_kit_api.MissingQuerySelectionIncludeAll),
+		})
+	}
+
+	return description
+}
+
+type GatlingMergeConfig struct {
+	Locations    *int
+	ArtifactMode string
+}
+
+func (m *GatlingMergeAction) Prepare(ctx context.Context, state *GatlingMergeState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, request.ExecutionId, "gatling.merge.prepare", exttracing.ExecutionContextAttributes(request.ExecutionContext)...)
+	result, err := m.prepare(state, request)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (m *GatlingMergeAction) prepare(state *GatlingMergeState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	var config GatlingMergeConfig
+	if err := extconversion.Convert(request.Config, &config); err != nil {
+		return nil, extension_kit.ToError("Failed to unmarshal the config.", err)
+	}
+	state.ExecutionId = request.ExecutionId
+	state.ExperimentKey = *request.ExecutionContext.ExperimentKey
+	state.ExperimentExecutionId = *request.ExecutionContext.ExecutionId
+	state.ArtifactMode = config.ArtifactMode
+	if state.ArtifactMode == artifactModeSimulationLog {
+		state.ArtifactMode = artifactModeFull
+	}
+	if config.Locations != nil && *config.Locations > 0 {
+		state.ExpectedLocations = *config.Locations
+	}
+	return nil, nil
+}
+
+func (m *GatlingMergeAction) Start(ctx context.Context, state *GatlingMergeState) (*action_kit_api.StartResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.merge.start", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := m.start(state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (m *GatlingMergeAction) start(state *GatlingMergeState) (result *action_kit_api.StartResult, err error) {
+	executionRoot := mergeExecutionRoot(state.ExecutionId)
+	defer func() {
+		if err != nil || (result != nil && result.Error != nil) {
+			removeMergeExecutionRoot(state.ExecutionId)
+		}
+	}()
+	reportFolder := fmt.Sprintf("%v/report", executionRoot)
+	reportDir, sources, pushedLogs, err := collectMergeInput(state, reportFolder, time.Now())
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to collect the pushed simulation logs.", err)
+	}
+	if len(sources) == 0 {
+		return &action_kit_api.StartResult{
+			Error: &action_kit_api.ActionKitError{
+				Status: extutil.Ptr(action_kit_api.Errored),
+				Title:  "No location pushed a simulation.log for this experiment execution, set STEADYBIT_EXTENSION_AGGREGATION_URL to this extension on all locations and merge after the load test.",
+			},
+		}, nil
+	}
+	state.ReportDir = reportDir
+	state.PushedLogs = pushedLogs
+
+	if err := exec.Command("cp", "-r", "gatling-maven-scaffold", executionRoot).Run(); err != nil {
+		return nil, extension_kit.ToError("Failed to copy gatling scaffold.", err)
+	}
+	command := mergeCommand(reportDir)
+	log.Info().Msgf("Merging %d simulation logs with command: %s", len(sources), strings.Join(command, " "))
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Dir = fmt.Sprintf("%v/gatling-maven-scaffold", executionRoot)
+	cmdState := extcmd.NewCmdState(cmd)
+	state.CmdStateID = cmdState.Id
+	if err := cmd.Start(); err != nil {
+		return nil, extension_kit.ToError("Failed to start command.", err)
+	}
+	state.Pid = cmd.Process.Pid
+	go func() {
+		if cmdErr := cmdState.Wait(); cmdErr != nil {
+			log.Error().Msgf("Failed to generate the merged report: %s", cmdErr)
+		}
+	}()
+
+	messages := []action_kit_api.Message{
+		markdownMessage(fmt.Sprintf("⏳ Merging the results of %d location(s): %s", len(sources), strings.Join(sources, ", "))),
+	}
+	if state.ExpectedLocations > len(sources) {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Only %d of %d locations pushed their results, the merged report is incomplete.", len(sources), state.ExpectedLocations),
+		})
+	}
+	return &action_kit_api.StartResult{Messages: new(messages)}, nil
+}
+
+// mergeExecutionRoot is the folder a merge copies the scaffold to and
+// generates the report in. Unlike the one of a local run, it isn't managed by
+// action_kit_sdk's file download handling, so the merge removes it itself.
+func mergeExecutionRoot(executionId uuid.UUID) string {
+	return fmt.Sprintf("/tmp/steadybit/%v", executionId)
+}
+
+func removeMergeExecutionRoot(executionId uuid.UUID) {
+	if err := os.RemoveAll(mergeExecutionRoot(executionId)); err != nil {
+		log.Warn().Err(err).Msgf("Failed to remove the folder of merge %s", executionId)
+	}
+}
+
+// collectMergeInput links the simulation.log files pushed for the experiment
+// execution into a new report folder in reportFolder, which Gatling reads them
+// all from like from one run. The pushed files stay until the merged report
+// exists, so a failed merge can be retried. Returns the folder, the names the
+// logs were pushed under and their paths, nothing if there are none.
+func collectMergeInput(state *GatlingMergeState, reportFolder string, now time.Time) (string, []string, []string, error) {
+	logs, err := aggregatedLogs(state.ExperimentKey, state.ExperimentExecutionId)
+	if err != nil || len(logs) == 0 {
+		return "", nil, nil, err
+	}
+	// named like the reports Gatling generates, <simulation>-<timestamp>
+	reportDir := filepath.Join(reportFolder, "merged-"+strings.Replace(now.UTC().Format("20060102150405.000"), ".", "", 1))
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return "", nil, nil, err
+	}
+	sources := make([]string, 0, len(logs))
+	for _, simulationLog := range logs {
+		name := filepath.Base(simulationLog)
+		if err := linkOrCopy(simulationLog, filepath.Join(reportDir, name)); err != nil {
+			return "", nil, nil, err
+		}
+		sources = append(sources, strings.TrimSuffix(name, ".log"))
+	}
+	return reportDir, sources, logs, nil
+}
+
+// linkOrCopy hard-links source to target, or copies it if they are on
+// different file systems.
+func linkOrCopy(source, target string) error {
+	if err := os.Link(source, target); err == nil {
+		return nil
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// removePushedLogs removes the pushed simulation.log files that are part of
+// the merged report.
+func removePushedLogs(logs []string) {
+	for _, pushedLog := range logs {
+		if err := os.Remove(pushedLog); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msgf("Failed to remove the merged simulation log %s", pushedLog)
+		}
+	}
+}
+
+// mergeCommand generates the report from the simulation logs in reportDir
+// without running a simulation.
+func mergeCommand(reportDir string) []string {
+	return []string{
+		"mvn",
+		"gatling:test",
+		"-o", // offline
+		"-Dgatling.resultsFolder=" + filepath.Dir(reportDir),
+		"-Dgatling.reportsOnly=" + filepath.Base(reportDir),
+	}
+}
+
+func (m *GatlingMergeAction) Status(ctx context.Context, state *GatlingMergeState) (*action_kit_api.StatusResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.merge.status", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := m.status(state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (m *GatlingMergeAction) status(state *GatlingMergeState) (*action_kit_api.StatusResult, error) {
+	cmdState, err := extcmd.GetCmdState(state.CmdStateID)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to find command state", err)
+	}
+	stdOut := cmdState.GetLines(false)
+	stdOutToLog(stdOut)
+
+	result := action_kit_api.StatusResult{Messages: new(stdOutToMessages(stdOut))}
+	switch exitCode := cmdState.ExitCode(); exitCode {
+	case -1:
+		result.Completed = false
+	case 0:
+		log.Info().Msgf("Merged report generated")
+		result.Completed = true
+	default:
+		result.Completed = true
+		result.Error = &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Errored),
+			Title:  fmt.Sprintf("Generating the merged report errored, exit-code %d", exitCode),
+		}
+	}
+	return &result, nil
+}
+
+func (m *GatlingMergeAction) Stop(ctx context.Context, state *GatlingMergeState) (*action_kit_api.StopResult, error) {
+	_, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.merge.stop", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := m.stop(state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (m *GatlingMergeAction) stop(state *GatlingMergeState) (*action_kit_api.StopResult, error) {
+	// after the report is attached, which reads it from the folder
+	defer removeMergeExecutionRoot(state.ExecutionId)
+	if state.CmdStateID == "" {
+		log.Info().Msg("Merge not yet started, nothing to stop.")
+		return nil, nil
+	}
+	cmdState, err := extcmd.GetCmdState(state.CmdStateID)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to find command state", err)
+	}
+	extcmd.RemoveCmdState(state.CmdStateID)
+	gracefulKill(state.Pid, cmdState)
+
+	stdOut := cmdState.GetLines(true)
+	stdOutToLog(stdOut)
+	messages := stdOutToMessages(stdOut)
+	artifacts := make([]action_kit_api.Artifact, 0)
+	if !hasHtmlReport(state.ReportDir) {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: "Gatling did not generate the merged report.",
+		})
+		return &action_kit_api.StopResult{Artifacts: new(artifacts), Messages: new(messages)}, nil
+	}
+
+	removePushedLogs(state.PushedLogs)
+	messages = append(messages, summaryMessages([]string{state.ReportDir})...)
+	attached, warnings, err := reportArtifacts(state.ReportDir, state.ArtifactMode, nil)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to attach report", err)
+	}
+	artifacts = append(artifacts, attached...)
+	messages = append(messages, warnings...)
+	return &action_kit_api.StopResult{Artifacts: new(artifacts), Messages: new(messages)}, nil
+}
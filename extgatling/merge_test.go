@@ -0,0 +1,69 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectMergeInput(t *testing.T) {
+	useAggregationRoot(t)
+	writeFile(t, filepath.Join(aggregationRoot, "ADM-1", "42", "location-b_basicsimulation-2.log"), "b")
+	writeFile(t, filepath.Join(aggregationRoot, "ADM-1", "42", "location-a_basicsimulation-1.log"), "a")
+	writeFile(t, filepath.Join(aggregationRoot, "ADM-1", "43", "location-a_basicsimulation-3.log"), "other execution")
+	reportFolder := t.TempDir()
+	state := &GatlingMergeState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	reportDir, sources, pushedLogs, err := collectMergeInput(state, reportFolder, time.UnixMilli(1767268800123))
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(reportFolder, "merged-20260101120000123"), reportDir)
+	assert.Equal(t, []string{"location-a_basicsimulation-1", "location-b_basicsimulation-2"}, sources)
+	assert.FileExists(t, filepath.Join(reportDir, "location-a_basicsimulation-1.log"))
+	assert.FileExists(t, filepath.Join(reportDir, "location-b_basicsimulation-2.log"))
+	assert.FileExists(t, filepath.Join(aggregationRoot, "ADM-1", "42", "location-a_basicsimulation-1.log"), "kept until the merged report exists")
+	assert.FileExists(t, filepath.Join(aggregationRoot, "ADM-1", "43", "location-a_basicsimulation-3.log"))
+	assert.Len(t, pushedLogs, 2)
+
+	removePushedLogs(pushedLogs)
+	assert.NoFileExists(t, filepath.Join(aggregationRoot, "ADM-1", "42", "location-a_basicsimulation-1.log"))
+	assert.FileExists(t, filepath.Join(reportDir, "location-a_basicsimulation-1.log"))
+
+	assert.Equal(t, []string{
+		"mvn", "gatling:test", "-o",
+		"-Dgatling.resultsFolder=" + reportFolder,
+		"-Dgatling.reportsOnly=merged-20260101120000123",
+	}, mergeCommand(reportDir))
+}
+
+func Test_collectMergeInput_without_pushed_logs(t *testing.T) {
+	useAggregationRoot(t)
+
+	reportDir, sources, _, err := collectMergeInput(&GatlingMergeState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}, t.TempDir(), time.Now())
+
+	require.NoError(t, err)
+	assert.Empty(t, reportDir)
+	assert.Empty(t, sources)
+}
+
+func Test_stop_removes_the_merge_folder_but_keeps_the_pushed_logs(t *testing.T) {
+	useAggregationRoot(t)
+	pushedLog := filepath.Join(aggregationRoot, "ADM-1", "42", "location-a_basicsimulation-1.log")
+	writeFile(t, pushedLog, "a")
+	state := &GatlingMergeState{ExecutionId: uuid.New(), ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+	_, _, state.PushedLogs, _ = collectMergeInput(state, filepath.Join(mergeExecutionRoot(state.ExecutionId), "report"), time.Now())
+
+	_, err := (&GatlingMergeAction{}).stop(state)
+
+	require.NoError(t, err)
+	assert.NoDirExists(t, mergeExecutionRoot(state.ExecutionId))
+	assert.FileExists(t, pushedLog, "the merge never generated its report")
+}
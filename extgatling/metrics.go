@@ -0,0 +1,72 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	liveLabels = []string{"experiment_key", "execution_id", "simulation"}
+
+	requestsTotalDesc = prometheus.NewDesc("gatling_requests_total",
+		"Requests completed by the running simulation, by status.",
+		append(liveLabels, "status"), nil)
+	requestsPerSecondDesc = prometheus.NewDesc("gatling_requests_per_second",
+		"Requests completed per second over the last 10 seconds.",
+		liveLabels, nil)
+	errorRatioDesc = prometheus.NewDesc("gatling_error_ratio",
+		"Ratio of failed requests over the last 10 seconds, between 0 and 1.",
+		liveLabels, nil)
+	responseTimeDesc = prometheus.NewDesc("gatling_response_time_seconds",
+		"Response time percentiles over the last 10 seconds.",
+		append(liveLabels, "quantile"), nil)
+	activeUsersDesc = prometheus.NewDesc("gatling_active_users",
+		"Virtual users currently running.",
+		liveLabels, nil)
+)
+
+// liveMetricsCollector exposes the live statistics of the running local
+// simulations as Prometheus metrics. The series of a run disappear once it is
+// stopped.
+type liveMetricsCollector struct{}
+
+// NewLiveMetricsCollector returns the collector for the metrics of running
+// local simulations.
+func NewLiveMetricsCollector() prometheus.Collector {
+	return liveMetricsCollector{}
+}
+
+func (c liveMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- requestsTotalDesc
+	ch <- requestsPerSecondDesc
+	ch <- errorRatioDesc
+	ch <- responseTimeDesc
+	ch <- activeUsersDesc
+}
+
+func (c liveMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, run := range getLiveRuns() {
+		for simulation, stats := range run.Simulations(now, liveWindow) {
+			labels := []string{run.ExperimentKey, strconv.Itoa(run.ExperimentExecutionId), simulation}
+			ch <- prometheus.MustNewConstMetric(requestsTotalDesc, prometheus.CounterValue, float64(stats.TotalOk), append(labels, "ok")...)
+			ch <- prometheus.MustNewConstMetric(requestsTotalDesc, prometheus.CounterValue, float64(stats.TotalKo), append(labels, "ko")...)
+			ch <- prometheus.MustNewConstMetric(requestsPerSecondDesc, prometheus.GaugeValue, stats.RequestsPerSecond, labels...)
+			ch <- prometheus.MustNewConstMetric(errorRatioDesc, prometheus.GaugeValue, stats.ErrorRatio, labels...)
+			ch <- prometheus.MustNewConstMetric(activeUsersDesc, prometheus.GaugeValue, float64(stats.ActiveUsers), labels...)
+			for quantile, value := range map[string]float64{"0.5": stats.P50, "0.95": stats.P95, "0.99": stats.P99} {
+				if math.IsNaN(value) {
+					continue // no requests in the window
+				}
+				ch <- prometheus.MustNewConstMetric(responseTimeDesc, prometheus.GaugeValue, value/1000, append(labels, quantile)...)
+			}
+		}
+	}
+}
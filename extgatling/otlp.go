@@ -0,0 +1,244 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/exttracing"
+	"github.com/steadybit/extension-kit/extsignals"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const otlpScope = "github.com/steadybit/extension-gatling"
+
+// otlpExportInterval is how often the live metrics of the running simulations
+// are sent. A var so tests don't have to wait.
+var otlpExportInterval = liveWindow
+
+// otlpExporter sends the metrics to the collector, nil unless an endpoint is
+// configured.
+var otlpExporter sdkmetric.Exporter
+
+// StartOtlpMetricsExport sends the metrics of the local runs to the configured
+// OpenTelemetry collector, if any: the live metrics of the running simulations
+// every 10 seconds, and the statistics of the report once a run is stopped.
+func StartOtlpMetricsExport() {
+	if config.Config.OtlpMetricsEndpoint == "" {
+		return
+	}
+	exporter, err := otlpmetrichttp.New(context.Background(), otlpmetrichttp.WithEndpointURL(config.Config.OtlpMetricsEndpoint))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create the OTLP metrics exporter, metrics are not exported")
+		return
+	}
+	otlpExporter = exporter
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(otlpExportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				for _, run := range getLiveRuns() {
+					exportOtlpMetrics(liveOtlpMetrics(run, now))
+				}
+			}
+		}
+	}()
+	extsignals.AddSignalHandler(extsignals.SignalHandler{
+		Handler: func(_ os.Signal) {
+			close(stop)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = exporter.Shutdown(ctx)
+		},
+		Order: extsignals.OrderStopCustom,
+		Name:  "otlp-metrics",
+	})
+	log.Info().Msgf("Exporting metrics via OTLP to %s", config.Config.OtlpMetricsEndpoint)
+}
+
+// exportFinalOtlpMetrics sends the last live metrics of a stopped run and the
+// statistics of its reports in the background.
+func exportFinalOtlpMetrics(run *liveRun, state *GatlingLoadTestRunState, reportDirs []string) {
+	if otlpExporter == nil {
+		return
+	}
+	metrics := finalOtlpMetrics(run, state, reportDirs, time.Now())
+	go exportOtlpMetrics(metrics)
+}
+
+func exportOtlpMetrics(metrics []*metricdata.ResourceMetrics) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, m := range metrics {
+		if err := otlpExporter.Export(ctx, m); err != nil {
+			log.Warn().Err(err).Msg("Failed to export metrics via OTLP")
+			return
+		}
+	}
+}
+
+// liveOtlpMetrics converts the live statistics of a run, one resource per
+// simulation.
+func liveOtlpMetrics(run *liveRun, now time.Time) []*metricdata.ResourceMetrics {
+	var result []*metricdata.ResourceMetrics
+	for simulation, stats := range run.Simulations(now, liveWindow) {
+		metrics := []metricdata.Metrics{
+			{
+				Name:        "gatling.requests",
+				Description: "Requests completed by the simulation, by status.",
+				Unit:        "{request}",
+				Data: metricdata.Sum[int64]{
+					Temporality: metricdata.CumulativeTemporality,
+					IsMonotonic: true,
+					DataPoints: []metricdata.DataPoint[int64]{
+						{Attributes: attribute.NewSet(attribute.String("status", "ok")), StartTime: run.started, Time: now, Value: stats.TotalOk},
+						{Attributes: attribute.NewSet(attribute.String("status", "ko")), StartTime: run.started, Time: now, Value: stats.TotalKo},
+					},
+				},
+			},
+			gaugeMetric("gatling.requests.rate", "Requests completed per second over the last 10 seconds.", "{request}/s",
+				metricdata.DataPoint[float64]{Time: now, Value: stats.RequestsPerSecond}),
+			gaugeMetric("gatling.error_ratio", "Ratio of failed requests over the last 10 seconds, between 0 and 1.", "1",
+				metricdata.DataPoint[float64]{Time: now, Value: stats.ErrorRatio}),
+			gaugeMetric("gatling.active_users", "Virtual users currently running.", "{user}",
+				metricdata.DataPoint[float64]{Time: now, Value: float64(stats.ActiveUsers)}),
+		}
+		var responseTimes []metricdata.DataPoint[float64]
+		for _, q := range []struct {
+			quantile string
+			value    float64
+		}{{"0.5", stats.P50}, {"0.95", stats.P95}, {"0.99", stats.P99}} {
+			if math.IsNaN(q.value) {
+				continue // no requests in the window
+			}
+			responseTimes = append(responseTimes, metricdata.DataPoint[float64]{
+				Attributes: attribute.NewSet(attribute.String("quantile", q.quantile)),
+				Time:       now,
+				Value:      q.value / 1000,
+			})
+		}
+		if len(responseTimes) > 0 {
+			metrics = append(metrics, gaugeMetric("gatling.response_time", "Response time percentiles over the last 10 seconds.", "s", responseTimes...))
+		}
+		result = append(result, resourceMetrics(run.ExperimentKey, run.ExperimentExecutionId, simulation, metrics))
+	}
+	return result
+}
+
+// finalOtlpMetrics converts the last live statistics of a stopped run, if it
+// was followed, and the statistics of each request in its reports.
+func finalOtlpMetrics(run *liveRun, state *GatlingLoadTestRunState, reportDirs []string, now time.Time) []*metricdata.ResourceMetrics {
+	var result []*metricdata.ResourceMetrics
+	var started time.Time
+	if run != nil {
+		result = liveOtlpMetrics(run, now)
+		started = run.started
+	}
+	for _, reportDir := range reportDirs {
+		summary, err := readSimulationSummary(reportDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Warn().Err(err).Msgf("Failed to read the statistics of report %s", filepath.Base(reportDir))
+			}
+			continue
+		}
+		simulation := summary.Simulation
+		if run != nil && run.simulationOf(summary.Report) != "" {
+			simulation = run.simulationOf(summary.Report)
+		}
+		result = append(result, resourceMetrics(state.ExperimentKey, state.ExperimentExecutionId, simulation, reportOtlpMetrics(summary, started, now)))
+	}
+	return result
+}
+
+// reportOtlpMetrics converts the statistics of a report, labelled by request.
+// The request "Global" covers all requests of the simulation.
+func reportOtlpMetrics(summary *simulationSummary, started, now time.Time) []metricdata.Metrics {
+	var requests []metricdata.DataPoint[int64]
+	var responseTimes, throughput []metricdata.DataPoint[float64]
+	for _, request := range append([]requestSummary{summary.Global}, summary.Requests...) {
+		name := attribute.String("request", request.Name)
+		for status, value := range map[string]int64{"ok": request.Ok, "ko": request.Ko} {
+			requests = append(requests, metricdata.DataPoint[int64]{
+				Attributes: attribute.NewSet(name, attribute.String("status", status)),
+				StartTime:  started,
+				Time:       now,
+				Value:      value,
+			})
+		}
+		for statistic, value := range map[string]float64{
+			"min": request.Min, "mean": request.Mean, "max": request.Max,
+			"p50": request.P50, "p75": request.P75, "p95": request.P95, "p99": request.P99,
+		} {
+			responseTimes = append(responseTimes, metricdata.DataPoint[float64]{
+				Attributes: attribute.NewSet(name, attribute.String("statistic", statistic)),
+				Time:       now,
+				Value:      value / 1000,
+			})
+		}
+		throughput = append(throughput, metricdata.DataPoint[float64]{
+			Attributes: attribute.NewSet(name),
+			Time:       now,
+			Value:      request.Throughput,
+		})
+	}
+	return []metricdata.Metrics{
+		{
+			Name:        "gatling.report.requests",
+			Description: "Requests of the finished simulation, by request and status.",
+			Unit:        "{request}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints:  requests,
+			},
+		},
+		gaugeMetric("gatling.report.response_time", "Response time statistics of the finished simulation, by request.", "s", responseTimes...),
+		gaugeMetric("gatling.report.throughput", "Mean requests per second of the finished simulation, by request.", "{request}/s", throughput...),
+	}
+}
+
+func gaugeMetric(name, description, unit string, points ...metricdata.DataPoint[float64]) metricdata.Metrics {
+	return metricdata.Metrics{
+		Name:        name,
+		Description: description,
+		Unit:        unit,
+		Data:        metricdata.Gauge[float64]{DataPoints: points},
+	}
+}
+
+// resourceMetrics puts the metrics of a simulation into a resource identifying
+// the experiment execution, the simulation and the location it ran at.
+func resourceMetrics(experimentKey string, experimentExecutionId int, simulation string, metrics []metricdata.Metrics) *metricdata.ResourceMetrics {
+	attributes := append(exttracing.ExperimentAttributes(experimentKey, experimentExecutionId),
+		attribute.String("service.name", "steadybit-extension-gatling"),
+		attribute.String("gatling.simulation", simulation),
+		attribute.String("steadybit.location", selfLocation().Label),
+	)
+	return &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attributes...),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope:   instrumentation.Scope{Name: otlpScope},
+			Metrics: metrics,
+		}},
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func Test_liveOtlpMetrics(t *testing.T) {
+	now := time.Unix(1767268800, 0)
+	simulation := &liveSimulation{Name: "BasicSimulation", Ok: 9, Ko: 1, ActiveUsers: 3}
+	sample := simulation.sample(now.Unix())
+	sample.Ok = 9
+	sample.Ko = 1
+	sample.ResponseTimes = []int64{100, 200}
+	run := &liveRun{
+		ExperimentKey:         "ADM-1",
+		ExperimentExecutionId: 42,
+		started:               now.Add(-time.Minute),
+		simulations:           map[string]*liveSimulation{"BasicSimulation": simulation},
+	}
+
+	result := liveOtlpMetrics(run, now)
+
+	require.Len(t, result, 1)
+	assertResource(t, result[0], "ADM-1", 42, "BasicSimulation")
+	metrics := metricsByName(result[0])
+	requests := metrics["gatling.requests"].Data.(metricdata.Sum[int64])
+	assert.True(t, requests.IsMonotonic)
+	assert.Equal(t, int64(9), pointValue(t, requests.DataPoints, attribute.String("status", "ok")))
+	assert.Equal(t, int64(1), pointValue(t, requests.DataPoints, attribute.String("status", "ko")))
+	assert.InDelta(t, 0.1, metrics["gatling.error_ratio"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value, 0.001)
+	assert.InDelta(t, 3, metrics["gatling.active_users"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value, 0.001)
+	responseTimes := metrics["gatling.response_time"].Data.(metricdata.Gauge[float64]).DataPoints
+	assert.InDelta(t, 0.2, pointValue(t, responseTimes, attribute.String("quantile", "0.99")), 0.001)
+}
+
+func Test_finalOtlpMetrics_per_request_of_the_report(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	result := finalOtlpMetrics(nil, state, []string{reportDir}, time.Now())
+
+	require.Len(t, result, 1)
+	assertResource(t, result[0], "ADM-1", 42, "basicsimulation")
+	metrics := metricsByName(result[0])
+	requests := metrics["gatling.report.requests"].Data.(metricdata.Sum[int64]).DataPoints
+	assert.Len(t, requests, 6)
+	assert.Equal(t, int64(190), pointValue(t, requests, attribute.String("request", "Global"), attribute.String("status", "ok")))
+	assert.Equal(t, int64(10), pointValue(t, requests, attribute.String("request", "checkout / pay"), attribute.String("status", "ko")))
+	responseTimes := metrics["gatling.report.response_time"].Data.(metricdata.Gauge[float64]).DataPoints
+	assert.InDelta(t, 0.06, pointValue(t, responseTimes, attribute.String("request", "home"), attribute.String("statistic", "p95")), 0.001)
+	throughput := metrics["gatling.report.throughput"].Data.(metricdata.Gauge[float64]).DataPoints
+	assert.InDelta(t, 20, pointValue(t, throughput, attribute.String("request", "Global")), 0.001)
+}
+
+func Test_finalOtlpMetrics_uses_the_simulation_name_of_the_live_run(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+	run := &liveRun{
+		simulations: map[string]*liveSimulation{},
+		reports:     map[string]string{"basicsimulation-20260101120000123": "computerdatabase.BasicSimulation"},
+	}
+
+	result := finalOtlpMetrics(run, &GatlingLoadTestRunState{}, []string{reportDir}, time.Now())
+
+	require.Len(t, result, 1)
+	value, _ := result[0].Resource.Set().Value("gatling.simulation")
+	assert.Equal(t, "computerdatabase.BasicSimulation", value.AsString())
+}
+
+func assertResource(t *testing.T, m *metricdata.ResourceMetrics, experimentKey string, executionId int, simulation string) {
+	t.Helper()
+	attributes := m.Resource.Set()
+	value, _ := attributes.Value("steadybit.experiment_key")
+	assert.Equal(t, experimentKey, value.AsString())
+	value, _ = attributes.Value("steadybit.experiment_execution_id")
+	assert.Equal(t, int64(executionId), value.AsInt64())
+	value, _ = attributes.Value("gatling.simulation")
+	assert.Equal(t, simulation, value.AsString())
+	assert.True(t, attributes.HasValue("steadybit.location"))
+}
+
+func metricsByName(m *metricdata.ResourceMetrics) map[string]metricdata.Metrics {
+	result := map[string]metricdata.Metrics{}
+	for _, scope := range m.ScopeMetrics {
+		for _, metric := range scope.Metrics {
+			result[metric.Name] = metric
+		}
+	}
+	return result
+}
+
+func pointValue[N int64 | float64](t *testing.T, points []metricdata.DataPoint[N], attributes ...attribute.KeyValue) N {
+	t.Helper()
+	want := attribute.NewSet(attributes...)
+	for _, point := range points {
+		if point.Attributes.Equals(&want) {
+			return point.Value
+		}
+	}
+	require.Failf(t, "no data point", "attributes %v", attributes)
+	return 0
+}
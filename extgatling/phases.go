@@ -0,0 +1,243 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	phaseBefore = "before"
+	phaseDuring = "during"
+	phaseAfter  = "after"
+)
+
+// attackPhase is the window of a run the attack of the experiment took place
+// in. The statistics of the run are split into before, during and after it.
+type attackPhase struct {
+	StartSpec string `json:"startSpec"`
+	EndSpec   string `json:"endSpec,omitempty"`
+	// resolved when the step starts, epoch millis, End is 0 if the attack
+	// lasts until the end of the run
+	Start int64 `json:"start,omitempty"`
+	End   int64 `json:"end,omitempty"`
+}
+
+// parseAttackPhase builds the attack phase from the parameters of the action,
+// nil if its start isn't set.
+func parseAttackPhase(config GatlingLoadTestRunConfig) (*attackPhase, error) {
+	phase := &attackPhase{StartSpec: strings.TrimSpace(config.PhaseAttackStart), EndSpec: strings.TrimSpace(config.PhaseAttackEnd)}
+	if phase.StartSpec == "" {
+		if phase.EndSpec != "" {
+			return nil, fmt.Errorf("the end of the attack phase needs its start")
+		}
+		return nil, nil
+	}
+	if err := phase.resolve(time.Now()); err != nil {
+		return nil, err
+	}
+	phase.Start, phase.End = 0, 0
+	return phase, nil
+}
+
+// resolve turns the start and end of the phase into timestamps, offsets are
+// relative to stepStart.
+func (p *attackPhase) resolve(stepStart time.Time) error {
+	start, err := resolvePhaseBoundary(p.StartSpec, stepStart)
+	if err != nil {
+		return fmt.Errorf("invalid start of the attack phase: %w", err)
+	}
+	var end int64
+	if p.EndSpec != "" {
+		if end, err = resolvePhaseBoundary(p.EndSpec, stepStart); err != nil {
+			return fmt.Errorf("invalid end of the attack phase: %w", err)
+		}
+		if end <= start {
+			return fmt.Errorf("the attack phase ends before it starts")
+		}
+	}
+	p.Start, p.End = start, end
+	return nil
+}
+
+// resolvePhaseBoundary parses an offset from the start of the step like "90s"
+// or a timestamp, either RFC 3339 or epoch millis, into epoch millis.
+func resolvePhaseBoundary(spec string, stepStart time.Time) (int64, error) {
+	if offset, err := time.ParseDuration(spec); err == nil {
+		return stepStart.Add(offset).UnixMilli(), nil
+	}
+	if at, err := time.Parse(time.RFC3339, spec); err == nil {
+		return at.UnixMilli(), nil
+	}
+	if millis, err := strconv.ParseInt(spec, 10, 64); err == nil && millis > 0 {
+		return millis, nil
+	}
+	return 0, fmt.Errorf("expected an offset like \"90s\" or a timestamp, got %q", spec)
+}
+
+// phaseSummary are the statistics of the requests of a simulation that ended
+// within a phase. Response times are in milliseconds.
+type phaseSummary struct {
+	Phase           string   `json:"phase"`
+	From            int64    `json:"from"` // epoch millis
+	To              int64    `json:"to"`   // epoch millis
+	Requests        int64    `json:"requests"`
+	Ko              int64    `json:"ko"`
+	ErrorPercentage float64  `json:"errorPercentage"`
+	P50             *float64 `json:"p50,omitempty"`
+	P95             *float64 `json:"p95,omitempty"`
+	P99             *float64 `json:"p99,omitempty"`
+	Throughput      float64  `json:"throughput"`
+}
+
+// phaseAggregator splits the requests of a simulation.log into the phases
+// before, during and after the attack.
+type phaseAggregator struct {
+	attack        *attackPhase
+	runStart      int64
+	lastEnd       int64
+	responseTimes map[string]*responseTimeHistogram
+	ko            map[string]int64
+}
+
+func newPhaseAggregator(attack *attackPhase) *phaseAggregator {
+	return &phaseAggregator{attack: attack, responseTimes: map[string]*responseTimeHistogram{}, ko: map[string]int64{}}
+}
+
+func (a *phaseAggregator) visit(record any) {
+	switch rec := record.(type) {
+	case *simulationLogRun:
+		a.runStart = rec.Start
+	case *requestRecord:
+		phase := a.attack.phaseOf(rec.End)
+		histogram, found := a.responseTimes[phase]
+		if !found {
+			histogram = &responseTimeHistogram{}
+			a.responseTimes[phase] = histogram
+		}
+		histogram.add(rec.End - rec.Start)
+		if !rec.Ok {
+			a.ko[phase]++
+		}
+		a.lastEnd = max(a.lastEnd, rec.End)
+	}
+}
+
+func (a *phaseAggregator) result() []phaseSummary {
+	attack, runStart := a.attack, a.runStart
+	lastEnd := max(a.lastEnd, runStart)
+	bounds := map[string][2]int64{
+		phaseBefore: {runStart, attack.Start},
+		phaseDuring: {attack.Start, lastEnd},
+	}
+	phases := []string{phaseBefore, phaseDuring}
+	if attack.End > 0 {
+		bounds[phaseDuring] = [2]int64{attack.Start, attack.End}
+		bounds[phaseAfter] = [2]int64{attack.End, lastEnd}
+		phases = append(phases, phaseAfter)
+	}
+
+	summaries := make([]phaseSummary, 0, len(phases))
+	for _, phase := range phases {
+		// only the part of the phase the simulation ran in counts
+		summary := phaseSummary{Phase: phase, From: clamp(bounds[phase][0], runStart, lastEnd), To: clamp(bounds[phase][1], runStart, lastEnd)}
+		summary.Ko = a.ko[phase]
+		if histogram := a.responseTimes[phase]; histogram != nil && histogram.total > 0 {
+			summary.Requests = histogram.total
+			summary.ErrorPercentage = float64(summary.Ko) * 100 / float64(summary.Requests)
+			summary.P50 = new(histogram.percentile(50))
+			summary.P95 = new(histogram.percentile(95))
+			summary.P99 = new(histogram.percentile(99))
+		}
+		if summary.To > summary.From {
+			summary.Throughput = float64(summary.Requests) * 1000 / float64(summary.To-summary.From)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func clamp(value, lower, upper int64) int64 {
+	return max(lower, min(value, upper))
+}
+
+func (p *attackPhase) phaseOf(timestamp int64) string {
+	if timestamp < p.Start {
+		return phaseBefore
+	}
+	if p.End > 0 && timestamp >= p.End {
+		return phaseAfter
+	}
+	return phaseDuring
+}
+
+// readSimulationLog reads all records of a complete simulation.log.
+func readSimulationLog(path string, visit func(record any)) error {
+	reader, err := openSimulationLog(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	return reader.readAvailable(visit)
+}
+
+// phaseTable renders the statistics of the phases as a markdown table.
+func phaseTable(phases []phaseSummary) string {
+	var table strings.Builder
+	table.WriteString("| Phase | Time (UTC) | Requests | Errors | p50 | p95 | p99 | Throughput |\n")
+	table.WriteString("|---|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, phase := range phases {
+		_, _ = fmt.Fprintf(&table, "| %s | %s – %s | %d | %s%% | %s | %s | %s | %s req/s |\n",
+			phase.Phase, formatClock(phase.From), formatClock(phase.To), phase.Requests, formatNumber(phase.ErrorPercentage),
+			formatMillis(phase.P50), formatMillis(phase.P95), formatMillis(phase.P99), formatNumber(phase.Throughput))
+	}
+	return table.String()
+}
+
+func formatClock(millis int64) string {
+	return time.UnixMilli(millis).UTC().Format("15:04:05")
+}
+
+func formatMillis(millis *float64) string {
+	if millis == nil || math.IsNaN(*millis) {
+		return "-"
+	}
+	return formatNumber(*millis) + " ms"
+}
+
+// phaseStatistics returns the statistics by phase of each analyzed report by
+// report folder, along with the messages showing them.
+func phaseStatistics(reportDirs []string, analyses map[string]*reportAnalysis) (map[string][]phaseSummary, []action_kit_api.Message) {
+	byReport := map[string][]phaseSummary{}
+	var messages []action_kit_api.Message
+	for _, reportDir := range reportDirs {
+		name := filepath.Base(reportDir)
+		analysis := analyses[reportDir]
+		if analysis == nil || analysis.phases == nil {
+			continue
+		}
+		if analysis.err != nil {
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("No statistics by phase for report %s: %s", name, analysis.err),
+			})
+			continue
+		}
+		phases := analysis.phases.result()
+		byReport[reportDir] = phases
+		messages = append(messages,
+			markdownMessage(fmt.Sprintf("### Before, During and After the Attack of %s", name)),
+			markdownMessage(phaseTable(phases)))
+	}
+	return byReport, messages
+}
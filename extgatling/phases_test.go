@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAttackPhase(t *testing.T) {
+	phase, err := parseAttackPhase(GatlingLoadTestRunConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, phase)
+
+	phase, err = parseAttackPhase(GatlingLoadTestRunConfig{PhaseAttackStart: "60s", PhaseAttackEnd: " 2m "})
+	require.NoError(t, err)
+	assert.Equal(t, &attackPhase{StartSpec: "60s", EndSpec: "2m"}, phase)
+
+	for _, config := range []GatlingLoadTestRunConfig{
+		{PhaseAttackEnd: "2m"},
+		{PhaseAttackStart: "soon"},
+		{PhaseAttackStart: "2m", PhaseAttackEnd: "60s"},
+	} {
+		_, err = parseAttackPhase(config)
+		assert.Error(t, err, config)
+	}
+}
+
+func Test_attackPhase_resolve(t *testing.T) {
+	stepStart := time.Unix(1767268800, 0)
+
+	phase := &attackPhase{StartSpec: "90s", EndSpec: "2026-01-01T12:05:00Z"}
+	require.NoError(t, phase.resolve(stepStart))
+	assert.Equal(t, stepStart.Add(90*time.Second).UnixMilli(), phase.Start)
+	assert.Equal(t, stepStart.Add(5*time.Minute).UnixMilli(), phase.End)
+
+	phase = &attackPhase{StartSpec: "1767268860000"}
+	require.NoError(t, phase.resolve(stepStart))
+	assert.Equal(t, int64(1767268860000), phase.Start)
+	assert.Zero(t, phase.End)
+}
+
+func Test_phaseAggregator(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start+1000, start+1100, true, "").
+		request(nil, "home", start+9000, start+9200, true, "").
+		request(nil, "home", start+10000, start+10500, false, "boom").
+		request(nil, "home", start+12000, start+13000, true, "").
+		request(nil, "home", start+20000, start+20100, true, "").
+		bytes()
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), content, 0644))
+
+	aggregator := newPhaseAggregator(&attackPhase{Start: start + 10000, End: start + 15000})
+	require.NoError(t, readSimulationLog(filepath.Join(reportDir, "simulation.log"), aggregator.visit))
+	phases := aggregator.result()
+
+	require.Len(t, phases, 3)
+	assert.Equal(t, phaseSummary{Phase: phaseBefore, From: start, To: start + 10000, Requests: 2, P50: new(100.0), P95: new(200.0), P99: new(200.0), Throughput: 0.2}, phases[0])
+	assert.Equal(t, phaseSummary{Phase: phaseDuring, From: start + 10000, To: start + 15000, Requests: 2, Ko: 1, ErrorPercentage: 50, P50: new(500.0), P95: new(1000.0), P99: new(1000.0), Throughput: 0.4}, phases[1])
+	assert.Equal(t, phaseSummary{Phase: phaseAfter, From: start + 15000, To: start + 20100, Requests: 1, P50: new(100.0), P95: new(100.0), P99: new(100.0), Throughput: 1000.0 / 5100}, phases[2])
+
+	assert.Equal(t, "| Phase | Time (UTC) | Requests | Errors | p50 | p95 | p99 | Throughput |\n"+
+		"|---|---|---:|---:|---:|---:|---:|---:|\n"+
+		"| before | 12:00:00 – 12:00:10 | 2 | 0% | 100 ms | 200 ms | 200 ms | 0.2 req/s |\n"+
+		"| during | 12:00:10 – 12:00:15 | 2 | 50% | 500 ms | 1000 ms | 1000 ms | 0.4 req/s |\n"+
+		"| after | 12:00:15 – 12:00:20 | 1 | 0% | 100 ms | 100 ms | 100 ms | 0.2 req/s |\n", phaseTable(phases))
+}
+
+func Test_phaseAggregator_attack_until_the_end(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start+1000, start+2000, true, "").
+		bytes()
+	require.NoError(t, os.MkdirAll(reportDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reportDir, "simulation.log"), content, 0644))
+
+	// the attack started before the simulation did
+	aggregator := newPhaseAggregator(&attackPhase{Start: start - 5000})
+	require.NoError(t, readSimulationLog(filepath.Join(reportDir, "simulation.log"), aggregator.visit))
+	phases := aggregator.result()
+
+	require.Len(t, phases, 2)
+	assert.Equal(t, phaseSummary{Phase: phaseBefore, From: start, To: start}, phases[0])
+	assert.Equal(t, int64(1), phases[1].Requests)
+	assert.Contains(t, phaseTable(phases), "| before | 12:00:00 – 12:00:00 | 0 | 0% | - | - | - | 0 req/s |")
+}
+
+func Test_summaryArtifact_includes_the_phases(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+
+	artifacts, _, err := reportArtifacts(reportDir, artifactModeSummary, []phaseSummary{{Phase: phaseBefore, Requests: 3}})
+
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	content, err := base64.StdEncoding.DecodeString(artifacts[0].Data)
+	require.NoError(t, err)
+	var summary simulationSummary
+	require.NoError(t, json.Unmarshal(content, &summary))
+	assert.Equal(t, []phaseSummary{{Phase: phaseBefore, Requests: 3}}, summary.Phases)
+}
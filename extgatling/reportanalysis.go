@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// reportAnalysis is what Stop aggregates from the simulation.log of a report:
+// the request breakdown and, if asked for, the statistics by phase and the
+// CSV export. The log is read once, every record is handed to all
+// aggregators.
+type reportAnalysis struct {
+	breakdown *breakdownAggregator
+	// nil without attack phase
+	phases *phaseAggregator
+	// nil without CSV export
+	csv *csvAggregator
+	// why the simulation.log could not be read
+	err error
+}
+
+func (a *reportAnalysis) visit(record any) {
+	a.breakdown.visit(record)
+	if a.phases != nil {
+		a.phases.visit(record)
+	}
+	if a.csv != nil {
+		a.csv.visit(record)
+	}
+}
+
+// analyzeReports reads the simulation.log of each report, by report folder.
+func analyzeReports(reportDirs []string, attack *attackPhase, csvExport bool) map[string]*reportAnalysis {
+	analyses := make(map[string]*reportAnalysis, len(reportDirs))
+	for _, reportDir := range reportDirs {
+		analysis := &reportAnalysis{breakdown: newBreakdownAggregator()}
+		if attack != nil {
+			analysis.phases = newPhaseAggregator(attack)
+		}
+		if csvExport {
+			analysis.csv = newCsvAggregator()
+		}
+		if err := readSimulationLog(filepath.Join(reportDir, "simulation.log"), analysis.visit); err != nil {
+			if os.IsNotExist(err) {
+				log.Warn().Msgf("No simulation.log found for report %s", filepath.Base(reportDir))
+			} else {
+				log.Warn().Err(err).Msgf("Failed to aggregate the simulation.log of report %s", filepath.Base(reportDir))
+			}
+			analysis.err = err
+		}
+		analyses[reportDir] = analysis
+	}
+	return analyses
+}
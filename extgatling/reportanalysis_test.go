@@ -0,0 +1,43 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_analyzeReports_feeds_all_aggregators(t *testing.T) {
+	start := int64(1767268800000)
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-1")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), string(newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start, start+100, true, "").
+		request(nil, "home", start+5000, start+5200, false, "boom").
+		bytes()))
+	missing := filepath.Join(t.TempDir(), "basicsimulation-2")
+
+	analyses := analyzeReports([]string{reportDir, missing}, &attackPhase{Start: start + 1000}, true)
+
+	analysis := analyses[reportDir]
+	require.NoError(t, analysis.err)
+	requests, _, totalKo := analysis.breakdown.result()
+	assert.Equal(t, []requestBreakdown{{Name: "home", Requests: 2, Ko: 1, P95: 200, P99: 200}}, requests)
+	assert.Equal(t, int64(1), totalKo)
+	phases := analysis.phases.result()
+	require.Len(t, phases, 2)
+	assert.Equal(t, int64(1), phases[1].Ko)
+	series, _ := analysis.csv.result()
+	assert.Len(t, series, 6)
+
+	assert.Error(t, analyses[missing].err)
+	artifacts, messages := csvArtifacts(missing, analyses[missing])
+	assert.Empty(t, artifacts)
+	assert.Len(t, messages, 1)
+	_, phaseMessages := phaseStatistics([]string{missing}, analyses)
+	assert.Len(t, phaseMessages, 1)
+}
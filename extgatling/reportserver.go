@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// ReportsPath is the path the HTML reports of recent executions are served on,
+// as /reports/<executionId>/<report>/.
+const ReportsPath = "/reports/"
+
+// retainedReportsRoot holds the reports kept after Stop. action_kit_sdk removes
+// the execution directory right after Stop, so they have to be moved out of it.
+var retainedReportsRoot = "/tmp/steadybit-reports"
+
+// reportPruneInterval is how often the expired reports are removed, at most.
+var reportPruneInterval = time.Hour
+
+// reportRetention returns how long reports are kept after a run, zero if they
+// aren't kept at all.
+func reportRetention() time.Duration {
+	return config.Config.ReportRetention
+}
+
+// StartReportPruning removes the expired reports now, left over from before a
+// restart, and then periodically, so they go away even if no run stops.
+func StartReportPruning() {
+	retention := reportRetention()
+	if retention <= 0 {
+		return
+	}
+	pruneRetainedReports(retention)
+	go func() {
+		ticker := time.NewTicker(min(reportPruneInterval, retention))
+		defer ticker.Stop()
+		for range ticker.C {
+			pruneRetainedReports(retention)
+		}
+	}()
+}
+
+// hasHtmlReport tells whether Gatling got to generate the HTML report in
+// reportDir, it doesn't if the simulation is aborted.
+func hasHtmlReport(reportDir string) bool {
+	_, err := os.Stat(filepath.Join(reportDir, "index.html"))
+	return err == nil
+}
+
+// retainReport moves the report folder reportDir of an execution to the
+// retained reports and returns the path it is served on. simulation.log is
+// dropped, the HTML report doesn't need it and it is by far the largest file.
+func retainReport(executionId uuid.UUID, reportDir string) (string, error) {
+	executionDir := filepath.Join(retainedReportsRoot, executionId.String())
+	if err := os.MkdirAll(executionDir, 0755); err != nil {
+		return "", err
+	}
+	name := filepath.Base(reportDir)
+	if err := os.Rename(reportDir, filepath.Join(executionDir, name)); err != nil {
+		return "", err
+	}
+	if err := os.Remove(filepath.Join(executionDir, name, "simulation.log")); err != nil && !os.IsNotExist(err) {
+		log.Warn().Err(err).Msgf("Failed to remove simulation.log from retained report %s", name)
+	}
+	return fmt.Sprintf("%s%s/%s/", ReportsPath, executionId, name), nil
+}
+
+// reportLink returns the absolute link to a retained report, or its path on the
+// extension if the extension doesn't know the URL it is reachable on.
+func reportLink(path string) string {
+	return strings.TrimSuffix(config.Config.ReportsBaseUrl, "/") + path
+}
+
+// reportMessage points to a retained report. Only an absolute link can be
+// opened from the experiment execution, a path is shown as it is.
+func reportMessage(name, path string) action_kit_api.Message {
+	link := reportLink(path)
+	message := fmt.Sprintf("Report %s is served by the extension on %s", name, link)
+	if link != path {
+		message = fmt.Sprintf("[Open Report %s](%s)", name, link)
+	}
+	return action_kit_api.Message{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: message,
+	}
+}
+
+// pruneRetainedReports removes the reports of all executions that are older
+// than the retention.
+func pruneRetainedReports(retention time.Duration) {
+	entries, err := os.ReadDir(retainedReportsRoot)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read the retained reports")
+		}
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) <= retention {
+			continue
+		}
+		log.Debug().Msgf("Removing expired reports of execution %s", entry.Name())
+		if err := os.RemoveAll(filepath.Join(retainedReportsRoot, entry.Name())); err != nil {
+			log.Warn().Err(err).Msgf("Failed to remove expired reports of execution %s", entry.Name())
+		}
+	}
+}
+
+// ServeReports serves the retained HTML reports. Listing the executions is not
+// possible, a request has to name the execution and the report.
+func ServeReports(w http.ResponseWriter, r *http.Request, _ []byte) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	segments := strings.SplitN(strings.TrimPrefix(r.URL.Path, ReportsPath), "/", 3)
+	if len(segments) < 2 || segments[1] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := uuid.Parse(segments[0]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	executionDir := filepath.Join(retainedReportsRoot, segments[0])
+	if info, err := os.Stat(executionDir); err != nil || time.Since(info.ModTime()) > reportRetention() {
+		http.NotFound(w, r)
+		return
+	}
+	http.StripPrefix(ReportsPath, http.FileServer(http.Dir(retainedReportsRoot))).ServeHTTP(w, r)
+}
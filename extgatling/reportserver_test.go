@@ -0,0 +1,84 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_retainReport_serves_the_report_without_simulation_log(t *testing.T) {
+	useRetainedReportsRoot(t, 24*time.Hour)
+	executionId := uuid.New()
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "index.html"), "<html/>")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), "run")
+
+	path, err := retainReport(executionId, reportDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/reports/"+executionId.String()+"/basicsimulation-20260101120000123/", path)
+	assert.NoDirExists(t, reportDir)
+	assert.Equal(t, http.StatusOK, serve(t, path).Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, "/reports/"+executionId.String()+"/basicsimulation-20260101120000123/simulation.log").Code)
+}
+
+func Test_ServeReports_refuses_listings_and_unknown_executions(t *testing.T) {
+	useRetainedReportsRoot(t, 24*time.Hour)
+	executionId := uuid.New()
+	writeFile(t, filepath.Join(retainedReportsRoot, executionId.String(), "report", "index.html"), "<html/>")
+
+	assert.Equal(t, http.StatusNotFound, serve(t, "/reports/").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, "/reports/"+executionId.String()+"/").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, "/reports/not-an-execution/report/index.html").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, "/reports/"+uuid.New().String()+"/report/index.html").Code)
+}
+
+func Test_pruneRetainedReports_removes_expired_executions(t *testing.T) {
+	useRetainedReportsRoot(t, 24*time.Hour)
+	executionId := uuid.New()
+	writeFile(t, filepath.Join(retainedReportsRoot, executionId.String(), "report", "index.html"), "<html/>")
+
+	pruneRetainedReports(time.Hour)
+	assert.DirExists(t, filepath.Join(retainedReportsRoot, executionId.String()))
+
+	pruneRetainedReports(0)
+	assert.NoDirExists(t, filepath.Join(retainedReportsRoot, executionId.String()))
+}
+
+func useRetainedReportsRoot(t *testing.T, retention time.Duration) {
+	t.Helper()
+	originalRoot, originalRetention := retainedReportsRoot, config.Config.ReportRetention
+	t.Cleanup(func() {
+		retainedReportsRoot, config.Config.ReportRetention = originalRoot, originalRetention
+	})
+	retainedReportsRoot, config.Config.ReportRetention = t.TempDir(), retention
+}
+
+func serve(t *testing.T, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	ServeReports(recorder, httptest.NewRequest(http.MethodGet, path, nil), nil)
+	return recorder
+}
+
+func Test_reportMessage_links_the_report_only_with_a_base_url(t *testing.T) {
+	defer func() { config.Config.ReportsBaseUrl = "" }()
+	path := "/reports/b5c2d9e4-7f3a-4e1b-9c8d-0a1b2c3d4e5f/basicsimulation-20260101120000123/"
+
+	config.Config.ReportsBaseUrl = ""
+	assert.Equal(t, "Report basicsimulation is served by the extension on "+path, reportMessage("basicsimulation", path).Message)
+
+	config.Config.ReportsBaseUrl = "http://steadybit-extension-gatling.steadybit-agent:8087/"
+	assert.Equal(t, "[Open Report basicsimulation](http://steadybit-extension-gatling.steadybit-agent:8087"+path+")", reportMessage("basicsimulation", path).Message)
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// requestSummary condenses the statistics Gatling computed for one request, a
+// group or -- named "Global" -- for all requests of a simulation. Response times
+// are in milliseconds. The percentiles are the ones Gatling is configured with
+// by default (charting.indicators in gatling.conf), the scaffold doesn't change
+// them.
+type requestSummary struct {
+	Name            string  `json:"name"`
+	Requests        int64   `json:"requests"`
+	Ok              int64   `json:"ok"`
+	Ko              int64   `json:"ko"`
+	ErrorPercentage float64 `json:"errorPercentage"`
+	Min             float64 `json:"min"`
+	Mean            float64 `json:"mean"`
+	Max             float64 `json:"max"`
+	P50             float64 `json:"p50"`
+	P75             float64 `json:"p75"`
+	P95             float64 `json:"p95"`
+	P99             float64 `json:"p99"`
+	Throughput      float64 `json:"throughput"`
+}
+
+// simulationSummary is the summary of one Gatling report.
+type simulationSummary struct {
+	Simulation string           `json:"simulation"`
+	Report     string           `json:"report"`
+	Global     requestSummary   `json:"global"`
+	Requests   []requestSummary `json:"requests"`
+	// statistics before, during and after the attack, if its time was given
+	Phases []phaseSummary `json:"phases,omitempty"`
+}
+
+// reportFolderSuffix matches the "-<timestamp>" Gatling appends to the
+// simulation name when naming a report folder.
+var reportFolderSuffix = regexp.MustCompile(`-\d+$`)
+
+// readSimulationSummary reads the statistics of the report in reportDir from
+// the js/stats.json file Gatling generates alongside the HTML report.
+func readSimulationSummary(reportDir string) (*simulationSummary, error) {
+	content, err := os.ReadFile(filepath.Join(reportDir, "js", "stats.json"))
+	if err != nil {
+		return nil, err
+	}
+	var root statsNode
+	if err := json.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	report := filepath.Base(reportDir)
+	summary := &simulationSummary{
+		Simulation: reportFolderSuffix.ReplaceAllString(report, ""),
+		Report:     report,
+		Global:     root.Stats.toSummary("Global"),
+		Requests:   make([]requestSummary, 0),
+	}
+	root.collectRequests(&summary.Requests)
+	sort.Slice(summary.Requests, func(i, j int) bool { return summary.Requests[i].Name < summary.Requests[j].Name })
+	return summary, nil
+}
+
+// readSimulationSummaries reads the statistics of the reports, skipping the
+// ones Gatling generated none for.
+func readSimulationSummaries(reportDirs []string) []*simulationSummary {
+	var summaries []*simulationSummary
+	for _, reportDir := range reportDirs {
+		summary, err := readSimulationSummary(reportDir)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to read the statistics of report %s", filepath.Base(reportDir))
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// statsNode is a node of the tree in js/stats.json: the root is the "All
+// Requests" group, groups contain requests and nested groups.
+type statsNode struct {
+	Type     string               `json:"type"`
+	Name     string               `json:"name"`
+	Path     string               `json:"path"`
+	Stats    statsValues          `json:"stats"`
+	Contents map[string]statsNode `json:"contents"`
+}
+
+type statsValues struct {
+	NumberOfRequests              statsTriple `json:"numberOfRequests"`
+	MinResponseTime               statsTriple `json:"minResponseTime"`
+	MaxResponseTime               statsTriple `json:"maxResponseTime"`
+	MeanResponseTime              statsTriple `json:"meanResponseTime"`
+	Percentiles1                  statsTriple `json:"percentiles1"`
+	Percentiles2                  statsTriple `json:"percentiles2"`
+	Percentiles3                  statsTriple `json:"percentiles3"`
+	Percentiles4                  statsTriple `json:"percentiles4"`
+	MeanNumberOfRequestsPerSecond statsTriple `json:"meanNumberOfRequestsPerSecond"`
+}
+
+type statsTriple struct {
+	Total statsNumber `json:"total"`
+	Ok    statsNumber `json:"ok"`
+	Ko    statsNumber `json:"ko"`
+}
+
+// statsNumber accepts both, numbers and strings, as Gatling writes "-" for
+// values it has nothing to compute from, e.g. the KO response times of a run
+// without errors.
+type statsNumber float64
+
+func (n *statsNumber) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*n = statsNumber(v)
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			parsed = 0
+		}
+		*n = statsNumber(parsed)
+	default:
+		*n = 0
+	}
+	return nil
+}
+
+func (n statsNode) collectRequests(requests *[]requestSummary) {
+	for _, child := range n.Contents {
+		if child.Type == "REQUEST" {
+			name := child.Path
+			if name == "" {
+				name = child.Name
+			}
+			*requests = append(*requests, child.Stats.toSummary(name))
+		}
+		child.collectRequests(requests)
+	}
+}
+
+func (v statsValues) toSummary(name string) requestSummary {
+	summary := requestSummary{
+		Name:       name,
+		Requests:   int64(v.NumberOfRequests.Total),
+		Ok:         int64(v.NumberOfRequests.Ok),
+		Ko:         int64(v.NumberOfRequests.Ko),
+		Min:        float64(v.MinResponseTime.Total),
+		Mean:       float64(v.MeanResponseTime.Total),
+		Max:        float64(v.MaxResponseTime.Total),
+		P50:        float64(v.Percentiles1.Total),
+		P75:        float64(v.Percentiles2.Total),
+		P95:        float64(v.Percentiles3.Total),
+		P99:        float64(v.Percentiles4.Total),
+		Throughput: float64(v.MeanNumberOfRequestsPerSecond.Total),
+	}
+	if summary.Requests > 0 {
+		summary.ErrorPercentage = float64(summary.Ko) * 100 / float64(summary.Requests)
+	}
+	return summary
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsJson is a trimmed down js/stats.json of a report with one top level
+// request and one request inside a group.
+const statsJson = `{
+  "type": "GROUP",
+  "name": "All Requests",
+  "path": "",
+  "stats": {
+    "numberOfRequests": {"total": 200, "ok": 190, "ko": 10},
+    "minResponseTime": {"total": 5, "ok": 5, "ko": 100},
+    "maxResponseTime": {"total": 900, "ok": 800, "ko": 900},
+    "meanResponseTime": {"total": 50, "ok": 45, "ko": 150},
+    "percentiles1": {"total": 40, "ok": 40, "ko": 120},
+    "percentiles2": {"total": 60, "ok": 55, "ko": 140},
+    "percentiles3": {"total": 300, "ok": 250, "ko": 800},
+    "percentiles4": {"total": 700, "ok": 600, "ko": 880},
+    "meanNumberOfRequestsPerSecond": {"total": 20, "ok": 19, "ko": 1}
+  },
+  "contents": {
+    "req_home-1234": {
+      "type": "REQUEST",
+      "name": "home",
+      "path": "home",
+      "stats": {
+        "numberOfRequests": {"total": 100, "ok": 100, "ko": 0},
+        "minResponseTime": {"total": 5, "ok": 5, "ko": "-"},
+        "maxResponseTime": {"total": 80, "ok": 80, "ko": "-"},
+        "meanResponseTime": {"total": 20, "ok": 20, "ko": "-"},
+        "percentiles1": {"total": 18, "ok": 18, "ko": "-"},
+        "percentiles2": {"total": 25, "ok": 25, "ko": "-"},
+        "percentiles3": {"total": 60, "ok": 60, "ko": "-"},
+        "percentiles4": {"total": 75, "ok": 75, "ko": "-"},
+        "meanNumberOfRequestsPerSecond": {"total": 10, "ok": 10, "ko": "-"}
+      }
+    },
+    "group_checkout-5678": {
+      "type": "GROUP",
+      "name": "checkout",
+      "path": "checkout",
+      "stats": {},
+      "contents": {
+        "req_checkout---pay-9abc": {
+          "type": "REQUEST",
+          "name": "pay",
+          "path": "checkout / pay",
+          "stats": {
+            "numberOfRequests": {"total": "100", "ok": "90", "ko": "10"},
+            "percentiles3": {"total": "500", "ok": "450", "ko": "800"},
+            "meanNumberOfRequestsPerSecond": {"total": "10", "ok": "9", "ko": "1"}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func Test_readSimulationSummary(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+
+	summary, err := readSimulationSummary(reportDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "basicsimulation", summary.Simulation)
+	assert.Equal(t, "basicsimulation-20260101120000123", summary.Report)
+	assert.Equal(t, requestSummary{
+		Name:            "Global",
+		Requests:        200,
+		Ok:              190,
+		Ko:              10,
+		ErrorPercentage: 5,
+		Min:             5,
+		Mean:            50,
+		Max:             900,
+		P50:             40,
+		P75:             60,
+		P95:             300,
+		P99:             700,
+		Throughput:      20,
+	}, summary.Global)
+	require.Len(t, summary.Requests, 2)
+	assert.Equal(t, "checkout / pay", summary.Requests[0].Name)
+	assert.Equal(t, int64(10), summary.Requests[0].Ko)
+	assert.InDelta(t, 10, summary.Requests[0].ErrorPercentage, 0.001)
+	assert.InDelta(t, 500, summary.Requests[0].P95, 0.001)
+	assert.Equal(t, "home", summary.Requests[1].Name)
+	assert.InDelta(t, 60, summary.Requests[1].P95, 0.001)
+}
+
+func Test_reportArtifacts_per_mode(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "simulation.log"), "run")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+
+	t.Run("none", func(t *testing.T) {
+		artifacts, messages, err := reportArtifacts(reportDir, artifactModeNone, nil)
+		require.NoError(t, err)
+		assert.Empty(t, artifacts)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("simulation log", func(t *testing.T) {
+		artifacts, _, err := reportArtifacts(reportDir, artifactModeSimulationLog, nil)
+		require.NoError(t, err)
+		require.Len(t, artifacts, 1)
+		assert.Equal(t, "$(experimentKey)_$(executionId)_basicsimulation-20260101120000123_simulation.log", artifacts[0].Label)
+		assert.Equal(t, "run", decodeArtifact(t, artifacts[0].Data))
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		artifacts, _, err := reportArtifacts(reportDir, artifactModeSummary, nil)
+		require.NoError(t, err)
+		require.Len(t, artifacts, 1)
+		assert.Equal(t, "$(experimentKey)_$(executionId)_basicsimulation-20260101120000123_summary.json", artifacts[0].Label)
+		var summary simulationSummary
+		require.NoError(t, json.Unmarshal([]byte(decodeArtifact(t, artifacts[0].Data)), &summary))
+		assert.Equal(t, int64(200), summary.Global.Requests)
+	})
+
+	t.Run("full report is the default", func(t *testing.T) {
+		artifacts, _, err := reportArtifacts(reportDir, "", nil)
+		require.NoError(t, err)
+		require.Len(t, artifacts, 1)
+		assert.Equal(t, "$(experimentKey)_$(executionId)_basicsimulation-20260101120000123_report.zip", artifacts[0].Label)
+	})
+}
+
+func decodeArtifact(t *testing.T, data string) string {
+	t.Helper()
+	content, err := base64.StdEncoding.DecodeString(data)
+	require.NoError(t, err)
+	return string(content)
+}
@@ -12,12 +12,17 @@ import (
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
 	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extmetrics"
+	"github.com/steadybit/extension-gatling/extstorage"
+	"github.com/steadybit/extension-gatling/exttracing"
+	"github.com/steadybit/extension-gatling/extwebhook"
 	extension_kit "github.com/steadybit/extension-kit"
 	"github.com/steadybit/extension-kit/extbuild"
 	"github.com/steadybit/extension-kit/extcmd"
 	"github.com/steadybit/extension-kit/extconversion"
-	"github.com/steadybit/extension-kit/extfile"
 	"github.com/steadybit/extension-kit/extutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -29,10 +34,30 @@ import (
 type GatlingLoadTestRunAction struct{}
 
 type GatlingLoadTestRunState struct {
-	Command     []string  `json:"command"`
-	Pid         int       `json:"pid"`
-	CmdStateID  string    `json:"cmdStateId"`
-	ExecutionId uuid.UUID `json:"executionId"`
+	Command               []string         `json:"command"`
+	Pid                   int              `json:"pid"`
+	CmdStateID            string           `json:"cmdStateId"`
+	ExecutionId           uuid.UUID        `json:"executionId"`
+	ExperimentKey         string           `json:"experimentKey"`
+	ExperimentExecutionId int              `json:"experimentExecutionId"`
+	ArtifactMode          string           `json:"artifactMode"`
+	CsvExport             bool             `json:"csvExport,omitempty"`
+	Slos                  *slos            `json:"slos,omitempty"`
+	Abort                 *abortCriteria   `json:"abort,omitempty"`
+	Baseline              *baselineOptions `json:"baseline,omitempty"`
+	AttackPhase           *attackPhase     `json:"attackPhase,omitempty"`
+	Sync                  *syncStart       `json:"sync,omitempty"`
+	Limits                *jvmLimits       `json:"limits,omitempty"`
+	// the abort criterion that stopped the run, if any
+	AbortedBy string `json:"abortedBy,omitempty"`
+	// why Gatling ran out of memory, if it did
+	OutOfMemory string `json:"outOfMemory,omitempty"`
+	// when the run was queued for a free slot, epoch millis, zero once it got one
+	QueuedAt      int64 `json:"queuedAt,omitempty"`
+	QueuePosition int   `json:"queuePosition,omitempty"`
+	// what the markdown widget was told last
+	Stage        string `json:"stage,omitempty"`
+	LastProgress int64  `json:"lastProgress,omitempty"`
 }
 
 // Make sure action implements all required interfaces
@@ -92,11 +117,226 @@ func (l *GatlingLoadTestRunAction) Describe() action_kit_api.ActionDescription {
 				Type:        action_kit_api.ActionParameterTypeString,
 				Required:    new(false),
 			},
+			{
+				Name:        "sloMaxP95",
+				Label:       "SLO: Max p95 Response Time",
+				Description: new("The run fails if the 95th percentile of the response times of all requests is higher."),
+				Type:        action_kit_api.ActionParameterTypeDuration,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "sloMaxP99",
+				Label:       "SLO: Max p99 Response Time",
+				Description: new("The run fails if the 99th percentile of the response times of all requests is higher."),
+				Type:        action_kit_api.ActionParameterTypeDuration,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "sloMaxErrorPercentage",
+				Label:       "SLO: Max Error Percentage",
+				Description: new("The run fails if a higher percentage of all requests failed."),
+				Type:        action_kit_api.ActionParameterTypePercentage,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "sloMinThroughput",
+				Label:       "SLO: Min Throughput",
+				Description: new("The run fails if fewer requests per second were sent on average."),
+				Type:        action_kit_api.ActionParameterTypeInteger,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "sloPerRequest",
+				Label:       "SLOs per Request",
+				Description: new("SLOs of single requests, by request name as shown in the report. Use \"<group> / <request>\" for requests in groups. The value lists the thresholds, e.g. \"p95=500ms, p99=1s, errors=1, throughput=10\"."),
+				Type:        action_kit_api.ActionParameterTypeKeyValue,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "abortMaxErrorPercentage",
+				Label:       "Abort: Max Error Percentage",
+				Description: new("Stop the run early and fail if the percentage of failed requests over the last 10 seconds stays higher for the time set in \"Abort After\"."),
+				Type:        action_kit_api.ActionParameterTypePercentage,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "abortMaxP99",
+				Label:       "Abort: Max p99 Response Time",
+				Description: new("Stop the run early and fail if the 99th percentile of the response times over the last 10 seconds stays higher for the time set in \"Abort After\"."),
+				Type:        action_kit_api.ActionParameterTypeDuration,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:         "abortAfter",
+				Label:        "Abort After",
+				Description:  new("How long an abort criterion has to be exceeded before the run is stopped."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(false),
+				Advanced:     new(true),
+			},
+			{
+				Name:        "baselineSave",
+				Label:       "Save as Baseline",
+				Description: new("Store the statistics of the run under this name, replacing the baseline saved under it before."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "baselineCompare",
+				Label:       "Compare to Baseline",
+				Description: new("Compare the statistics of the run to the baseline saved under this name."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "baselineMaxP95Increase",
+				Label:       "Baseline: Max p95 Increase",
+				Description: new("The run fails if the 95th percentile of the response times of a request increased by more percent than this compared to the baseline."),
+				Type:        action_kit_api.ActionParameterTypePercentage,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "baselineMaxErrorPercentageIncrease",
+				Label:       "Baseline: Max Error Percentage Increase",
+				Description: new("The run fails if the error percentage of a request increased by more percentage points than this compared to the baseline."),
+				Type:        action_kit_api.ActionParameterTypePercentage,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "baselineMaxThroughputDecrease",
+				Label:       "Baseline: Max Throughput Decrease",
+				Description: new("The run fails if the throughput of a request decreased by more percent than this compared to the baseline."),
+				Type:        action_kit_api.ActionParameterTypePercentage,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "phaseAttackStart",
+				Label:       "Phases: Attack Start",
+				Description: new("Start of the attack, as offset from the start of this step like \"60s\" or as timestamp, RFC 3339 or epoch millis. The statistics of the run are shown before, during and after the attack."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "phaseAttackEnd",
+				Label:       "Phases: Attack End",
+				Description: new("End of the attack, like its start. If omitted, the attack lasts until the end of the run."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "syncLocations",
+				Label:       "Synchronized Start: Locations",
+				Description: new("Start injecting on this many locations at the same instant. They compile the simulation beforehand and wait for each other, which requires STEADYBIT_EXTENSION_AGGREGATION_URL."),
+				Type:        action_kit_api.ActionParameterTypeInteger,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:         "syncTimeout",
+				Label:        "Synchronized Start: Timeout",
+				Description:  new("How long the locations wait for each other before the step errors."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("60s"),
+				Required:     new(false),
+				Advanced:     new(true),
+			},
+			{
+				Name:         "loadDistribution",
+				Label:        "Synchronized Start: Load Distribution",
+				Description:  new("Whether each location runs the full simulation or the locations share the load by their weight. A simulation reads its share from the steadybit.location.* system properties."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(loadDistributionFull),
+				Required:     new(false),
+				Advanced:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Full load on each location", Value: loadDistributionFull},
+					action_kit_api.ExplicitParameterOption{Label: "Distribute across locations", Value: loadDistributionDistribute},
+				}),
+			},
+			{
+				Name:        "jvmHeap",
+				Label:       "JVM: Heap Size",
+				Description: new("Maximum heap of the Gatling JVM, like 512m or 2g. Defaults to STEADYBIT_EXTENSION_JVM_HEAP."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "jvmOptions",
+				Label:       "JVM: Options",
+				Description: new("Further options of the Gatling JVM like GC settings, separated by spaces, like -XX:+UseZGC. Defaults to STEADYBIT_EXTENSION_JVM_OPTIONS."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "memoryLimit",
+				Label:       "JVM: Memory Limit (MB)",
+				Description: new("Memory Maven and the Gatling JVM may use together, enforced with a cgroup. Defaults to STEADYBIT_EXTENSION_RUN_MEMORY_LIMIT_MB."),
+				Type:        action_kit_api.ActionParameterTypeInteger,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:        "cpuLimit",
+				Label:       "JVM: CPU Limit",
+				Description: new("CPU cores Maven and the Gatling JVM may use together, like 2 or 0.5, enforced with a cgroup. Defaults to STEADYBIT_EXTENSION_RUN_CPU_LIMIT."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Advanced:    new(true),
+			},
+			{
+				Name:         "artifactMode",
+				Label:        "Attached Report",
+				Description:  new("What to attach to the experiment execution after the run: the full HTML report, a summary of the statistics, the raw simulation.log or nothing."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(artifactModeFull),
+				Required:     new(false),
+				Advanced:     new(true),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Full report (zip)", Value: artifactModeFull},
+					action_kit_api.ExplicitParameterOption{Label: "Summary only (json)", Value: artifactModeSummary},
+					action_kit_api.ExplicitParameterOption{Label: "simulation.log only", Value: artifactModeSimulationLog},
+					action_kit_api.ExplicitParameterOption{Label: "None", Value: artifactModeNone},
+				}),
+			},
+			{
+				Name:         "csvExport",
+				Label:        "Attach CSV Files",
+				Description:  new("Attach the throughput, errors and response time percentiles per second and a response time histogram per request as CSV files."),
+				Type:         action_kit_api.ActionParameterTypeBoolean,
+				DefaultValue: new("true"),
+				Required:     new(false),
+				Advanced:     new(true),
+			},
 		},
 		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
 			CallInterval: new("5s"),
 		}),
 		Stop: new(action_kit_api.MutatingEndpointReference{}),
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.MarkdownWidget{
+				Type:        action_kit_api.ComSteadybitWidgetMarkdown,
+				Title:       "Gatling",
+				MessageType: markdownMessageType,
+				Append:      true,
+			},
+		}),
 	}
 
 	if config.Config.EnableLocationSelection {
@@ -120,22 +360,91 @@ func (l *GatlingLoadTestRunAction) Describe() action_kit_api.ActionDescription {
 }
 
 type GatlingLoadTestRunConfig struct {
-	Parameter  []map[string]string
-	File       string
-	Simulation string
+	Parameter                          []map[string]string
+	File                               string
+	Simulation                         string
+	ArtifactMode                       string
+	SloMaxP95                          *int
+	SloMaxP99                          *int
+	SloMaxErrorPercentage              *float64
+	SloMinThroughput                   *float64
+	SloPerRequest                      []map[string]string
+	AbortMaxErrorPercentage            *float64
+	AbortMaxP99                        *int
+	AbortAfter                         *int
+	BaselineSave                       string
+	BaselineCompare                    string
+	BaselineMaxP95Increase             *float64
+	BaselineMaxErrorPercentageIncrease *float64
+	BaselineMaxThroughputDecrease      *float64
+	PhaseAttackStart                   string
+	PhaseAttackEnd                     string
+	CsvExport                          bool
+	SyncLocations                      *int
+	SyncTimeout                        *int
+	LoadDistribution                   string
+	JvmHeap                            string
+	JvmOptions                         string
+	MemoryLimit                        *int
+	CpuLimit                           string
+}
+
+func (l *GatlingLoadTestRunAction) Prepare(ctx context.Context, state *GatlingLoadTestRunState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, request.ExecutionId, "gatling.run.prepare", exttracing.ExecutionContextAttributes(request.ExecutionContext)...)
+	result, err := l.prepare(ctx, state, request)
+	if err != nil || result != nil && result.Error != nil {
+		releaseRunSlot(request.ExecutionId)
+	} else {
+		runSlotPrepared(request.ExecutionId, time.Now())
+	}
+	exttracing.End(span, err)
+	return result, err
 }
 
-func (l *GatlingLoadTestRunAction) Prepare(_ context.Context, state *GatlingLoadTestRunState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+func (l *GatlingLoadTestRunAction) prepare(ctx context.Context, state *GatlingLoadTestRunState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
 	var config GatlingLoadTestRunConfig
 	if err := extconversion.Convert(request.Config, &config); err != nil {
 		return nil, extension_kit.ToError("Failed to unmarshal the config.", err)
 	}
+	slos, err := parseSlos(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid SLOs.", err)
+	}
+	baseline, err := parseBaselineOptions(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid baseline.", err)
+	}
+	attack, err := parseAttackPhase(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid attack phase.", err)
+	}
+	syncStart, err := parseSyncStart(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid synchronized start.", err)
+	}
+	limits, err := parseJvmLimits(config)
+	if err != nil {
+		return nil, extension_kit.ToError("Invalid JVM limits.", err)
+	}
+	// reserved before copying and compiling, which take the location's CPU as
+	// well. A synchronized start can't wait in the queue, the other locations
+	// would time out.
+	queue := syncStart == nil && runQueueTimeout() > 0
+	if reserveRunSlot(request.ExecutionId, queue, time.Now()) < 0 {
+		return nil, extension_kit.ToError("Gatling location is busy, try another location or later.", busyError())
+	}
 	executionRoot := fmt.Sprintf("/tmp/steadybit/%v", request.ExecutionId) //Folder is managed by action_kit_sdk's file download handling
+	if err := checkFreeDiskSpace(executionRoot); err != nil {
+		return nil, extension_kit.ToError("Not enough disk space to run Gatling.", err)
+	}
 	reportFolder := fmt.Sprintf("%v/report", executionRoot)
 	if err := os.Mkdir(reportFolder, 0755); err != nil {
 		return nil, extension_kit.ToError("Failed to create report folder.", err)
 	}
-	if err := exec.Command("cp", "-r", "gatling-maven-scaffold", executionRoot).Run(); err != nil {
+	_, copySpan := exttracing.StartSpan(ctx, "gatling.run.prepare.copy-scaffold")
+	err = exec.Command("cp", "-r", "gatling-maven-scaffold", executionRoot).Run()
+	exttracing.End(copySpan, err)
+	if err != nil {
 		return nil, extension_kit.ToError("Failed to copy gatling scaffold.", err)
 	}
 	srcFolder := fmt.Sprintf("%v/gatling-maven-scaffold/src/test/code", executionRoot)
@@ -143,23 +452,9 @@ func (l *GatlingLoadTestRunAction) Prepare(_ context.Context, state *GatlingLoad
 		return nil, extension_kit.ToError("Failed to create src folder.", err)
 	}
 
-	var messages []action_kit_api.Message
-	if filepath.Ext(config.File) == ".zip" {
-		log.Info().Msgf("Extracting %s to %s", config.File, srcFolder)
-		skipped, err := unzip(config.File, srcFolder)
-		if err != nil {
-			return nil, extension_kit.ToError("Failed to unzip file.", err)
-		}
-		if len(skipped) > 0 {
-			messages = append(messages, action_kit_api.Message{
-				Level:   extutil.Ptr(action_kit_api.Warn),
-				Message: fmt.Sprintf("Not extracted from %s, neither a file nor a folder: %s", filepath.Base(config.File), strings.Join(skipped, ", ")),
-			})
-		}
-	} else {
-		if err := exec.Command("mv", config.File, srcFolder).Run(); err != nil {
-			return nil, extension_kit.ToError("Failed to move file.", err)
-		}
+	messages, err := extractSources(ctx, config.File, srcFolder)
+	if err != nil {
+		return nil, err
 	}
 	oldSrcFolder := srcFolder
 	if HasFileWithSuffix(srcFolder, "scala") {
@@ -174,7 +469,7 @@ func (l *GatlingLoadTestRunAction) Prepare(_ context.Context, state *GatlingLoad
 	} else {
 		return nil, extension_kit.ExtensionError{Title: "No source files found."}
 	}
-	err := os.Rename(oldSrcFolder, srcFolder)
+	err = os.Rename(oldSrcFolder, srcFolder)
 	if err != nil {
 		return nil, extension_kit.ToError("Failed to prepare source folder.", err)
 	}
@@ -203,7 +498,26 @@ func (l *GatlingLoadTestRunAction) Prepare(_ context.Context, state *GatlingLoad
 	}
 
 	state.ExecutionId = request.ExecutionId
+	state.ExperimentKey = *request.ExecutionContext.ExperimentKey
+	state.ExperimentExecutionId = *request.ExecutionContext.ExecutionId
 	state.Command = command
+	state.ArtifactMode = config.ArtifactMode
+	state.CsvExport = config.CsvExport
+	state.Slos = slos
+	state.Abort = parseAbortCriteria(config)
+	state.Baseline = baseline
+	state.AttackPhase = attack
+	state.Sync = syncStart
+	state.Limits = limits
+
+	if state.Sync != nil {
+		// compile now, so Start only has to run the simulation
+		compileMessages, err := compileSimulation(ctx, state, executionRoot)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, compileMessages...)
+	}
 
 	if len(messages) == 0 {
 		return nil, nil
@@ -211,6 +525,31 @@ func (l *GatlingLoadTestRunAction) Prepare(_ context.Context, state *GatlingLoad
 	return &action_kit_api.PrepareResult{Messages: extutil.Ptr(messages)}, nil
 }
 
+// extractSources puts the uploaded sources into srcFolder, extracting zip files.
+func extractSources(ctx context.Context, file, srcFolder string) (messages []action_kit_api.Message, err error) {
+	_, span := exttracing.StartSpan(ctx, "gatling.run.prepare.extract-sources", trace.WithAttributes(attribute.String("file", filepath.Base(file))))
+	defer func() { exttracing.End(span, err) }()
+
+	if filepath.Ext(file) == ".zip" {
+		log.Info().Msgf("Extracting %s to %s", file, srcFolder)
+		skipped, err := unzip(file, srcFolder)
+		if err != nil {
+			return nil, extension_kit.ToError("Failed to unzip file.", err)
+		}
+		if len(skipped) > 0 {
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("Not extracted from %s, neither a file nor a folder: %s", filepath.Base(file), strings.Join(skipped, ", ")),
+			})
+		}
+	} else {
+		if err := exec.Command("mv", file, srcFolder).Run(); err != nil {
+			return nil, extension_kit.ToError("Failed to move file.", err)
+		}
+	}
+	return messages, nil
+}
+
 func HasFileWithSuffix(root, suffix string) bool {
 	found := false
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
@@ -231,18 +570,106 @@ func HasFileWithSuffix(root, suffix string) bool {
 	return found
 }
 
-func (l *GatlingLoadTestRunAction) Start(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StartResult, error) {
+func (l *GatlingLoadTestRunAction) Start(ctx context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StartResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.run.start", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := l.start(ctx, state)
+	if err != nil || result != nil && result.Error != nil {
+		releaseRunSlot(state.ExecutionId)
+	}
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (l *GatlingLoadTestRunAction) start(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StartResult, error) {
+	queue := state.Sync == nil && runQueueTimeout() > 0
+	now := time.Now()
+	position := claimRunSlot(state.ExecutionId, queue, now)
+	if position < 0 {
+		return nil, extension_kit.ToError("Gatling location is busy, try another location or later.", busyError())
+	}
+	if position > 0 {
+		log.Info().Msgf("Queued load test at position %d", position)
+		state.QueuedAt = now.UnixMilli()
+		state.Stage = stageQueued
+		return &action_kit_api.StartResult{Messages: new(queuedMessages(state, position))}, nil
+	}
+	if state.Sync != nil {
+		messages, err := arriveAtSyncStart(state, now)
+		if err != nil {
+			return &action_kit_api.StartResult{
+				Error: &action_kit_api.ActionKitError{
+					Status: extutil.Ptr(action_kit_api.Errored),
+					Title:  fmt.Sprintf("Synchronized start failed: %s", err),
+				},
+			}, nil
+		}
+		log.Info().Msgf("Waiting for %d locations at the synchronized start", state.Sync.Locations)
+		state.Stage = stageWaiting
+		return &action_kit_api.StartResult{Messages: new(messages)}, nil
+	}
+	return l.launch(state, nil)
+}
+
+// launch runs Gatling once the run got a slot and, with a synchronized start,
+// the barrier released it.
+func (l *GatlingLoadTestRunAction) launch(state *GatlingLoadTestRunState, released *barrierStatus) (*action_kit_api.StartResult, error) {
 	log.Info().Msgf("Starting Gatling load test with command: %s", strings.Join(state.Command, " "))
 	executionRoot := fmt.Sprintf("/tmp/steadybit/%v", state.ExecutionId) //Folder is managed by action_kit_sdk's file download handling
 	cmd := exec.Command(state.Command[0], state.Command[1:]...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	cmd.Dir = fmt.Sprintf("%v/gatling-maven-scaffold", executionRoot)
+	if state.AttackPhase != nil {
+		if err := state.AttackPhase.resolve(time.Now()); err != nil {
+			return nil, extension_kit.ToError("Invalid attack phase.", err)
+		}
+	}
+	var messages []action_kit_api.Message
+	if state.Limits != nil {
+		messages = append(messages, state.Limits.apply(executionRoot, fmt.Sprintf("steadybit-gatling-%v", state.ExecutionId))...)
+	}
+	run, err := startLiveRun(state, fmt.Sprintf("%v/report", executionRoot))
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to start receiving live statistics.", err)
+	}
+	if run.graphite != nil && state.Sync != nil {
+		// compiled in Prepare, the resources were already copied
+		cmd.Args = append(cmd.Args, graphiteProperties(run.graphite.Port())...)
+	} else if run.graphite != nil {
+		gatlingConf := fmt.Sprintf("%v/gatling-maven-scaffold/src/test/resources/gatling.conf", executionRoot)
+		if err := writeGraphiteConfig(gatlingConf, run.graphite.Port()); err != nil {
+			stopLiveRun(state.ExecutionId)
+			return nil, extension_kit.ToError("Failed to configure the graphite data writer.", err)
+		}
+	}
+	if released != nil {
+		messages = append(messages, releaseMessage(*released))
+		if state.Sync.Distribute {
+			share := shareOf(*released, state.Sync.Source)
+			cmd.Args = append(cmd.Args, share.properties()...)
+			messages = append(messages, shareMessage(share))
+		}
+		time.Sleep(time.Until(time.UnixMilli(state.Sync.StartAt)))
+	}
+	if state.Limits != nil && state.Limits.Cgroup != "" {
+		closeCgroup, err := startInCgroup(cmd, state.Limits.Cgroup)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to start Gatling in the cgroup of the run")
+			messages = append(messages, action_kit_api.Message{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("The memory and CPU limits are not enforced: %s", err),
+			})
+		} else {
+			defer closeCgroup()
+		}
+	}
 	cmdState := extcmd.NewCmdState(cmd)
 	state.CmdStateID = cmdState.Id
-	err := cmd.Start()
+	err = cmd.Start()
 	if err != nil {
+		stopLiveRun(state.ExecutionId)
 		return nil, extension_kit.ToError("Failed to start command.", err)
 	}
+	runSlotStarted(state.ExecutionId, cmdState.Id)
 
 	state.Pid = cmd.Process.Pid
 	go func() {
@@ -250,13 +677,34 @@ func (l *GatlingLoadTestRunAction) Start(_ context.Context, state *GatlingLoadTe
 			log.Error().Msgf("Failed to execute gatling: %s", cmdErr)
 		}
 	}()
+	extmetrics.RunStarted(actionId)
 	log.Info().Msgf("Started load test.")
 
 	state.Command = nil
-	return nil, nil
+	state.Stage = stageCompiling
+	if state.Sync != nil {
+		state.Stage = stageStarting
+	} else {
+		messages = append(messages, markdownMessage("⏳ Compiling the simulation"))
+	}
+	return &action_kit_api.StartResult{Messages: new(messages)}, nil
 }
 
-func (l *GatlingLoadTestRunAction) Status(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StatusResult, error) {
+func (l *GatlingLoadTestRunAction) Status(ctx context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StatusResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.run.status", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := l.status(ctx, state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (l *GatlingLoadTestRunAction) status(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StatusResult, error) {
+	if state.QueuedAt != 0 {
+		return l.statusQueued(state, time.Now())
+	}
+	refreshRunSlot(state.ExecutionId, time.Now())
+	if state.Sync != nil && state.Sync.WaitingSince != 0 {
+		return l.statusWaiting(state, time.Now())
+	}
 	log.Debug().Msgf("Checking Gatling status for %d\n", state.Pid)
 
 	cmdState, err := extcmd.GetCmdState(state.CmdStateID)
@@ -270,9 +718,30 @@ func (l *GatlingLoadTestRunAction) Status(_ context.Context, state *GatlingLoadT
 	exitCode := cmdState.ExitCode()
 	stdOut := cmdState.GetLines(false)
 	stdOutToLog(stdOut)
+	detectOutOfMemory(state, stdOut)
 	if exitCode == -1 {
 		log.Debug().Msgf("Gatling is still running")
 		result.Completed = false
+		reportFolder := fmt.Sprintf("/tmp/steadybit/%v/report", state.ExecutionId)
+		if size, exceeded := reportSizeExceeded(reportFolder); exceeded {
+			log.Warn().Msgf("Report folder grew to %s, stopping Gatling", formatSize(size))
+			gracefulKill(state.Pid, cmdState)
+			result.Completed = true
+			result.Error = &action_kit_api.ActionKitError{
+				Status: extutil.Ptr(action_kit_api.Errored),
+				Title:  fmt.Sprintf("Gatling run stopped, its report grew to %s and exceeds the limit of %d MB.", formatSize(size), config.Config.MaxReportSizeMb),
+			}
+		}
+		if run := getLiveRun(state.ExecutionId); !result.Completed && state.Abort != nil && run != nil {
+			now := time.Now()
+			if reason := state.Abort.check(run.Simulations(now, liveWindow), now); reason != "" {
+				log.Warn().Msgf("Abort criterion exceeded, stopping Gatling: %s", reason)
+				gracefulKill(state.Pid, cmdState)
+				state.AbortedBy = reason
+				result.Completed = true
+				result.Error = abortError(reason)
+			}
+		}
 	} else if exitCode == 0 {
 		log.Info().Msgf("Gatling run completed successfully")
 		result.Completed = true
@@ -282,6 +751,9 @@ func (l *GatlingLoadTestRunAction) Status(_ context.Context, state *GatlingLoadT
 			Status: extutil.Ptr(action_kit_api.Failed),
 			Title:  "Gatling run ended with failing assertions. Reports are attached.",
 		}
+	} else if state.OutOfMemory != "" {
+		result.Completed = true
+		result.Error = outOfMemoryError(state.OutOfMemory)
 	} else {
 		result.Completed = true
 		result.Error = &action_kit_api.ActionKitError{
@@ -291,13 +763,102 @@ func (l *GatlingLoadTestRunAction) Status(_ context.Context, state *GatlingLoadT
 	}
 
 	messages := stdOutToMessages(stdOut)
+	if run := getLiveRun(state.ExecutionId); run != nil && exitCode == -1 {
+		now := time.Now()
+		messages = append(messages, liveMessages(run, now)...)
+		messages = append(messages, progressMessages(state, run, now)...)
+		reportSyncStarted(state, fmt.Sprintf("/tmp/steadybit/%v/report", state.ExecutionId))
+		messages = append(messages, syncSkewMessages(state)...)
+	}
 	log.Debug().Msgf("Returning %d messages", len(messages))
 
 	result.Messages = new(messages)
 	return &result, nil
 }
 
-func (l *GatlingLoadTestRunAction) Stop(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StopResult, error) {
+// statusQueued launches a queued run once it gets a slot, or fails it once
+// it waited longer than the queue timeout.
+func (l *GatlingLoadTestRunAction) statusQueued(state *GatlingLoadTestRunState, now time.Time) (*action_kit_api.StatusResult, error) {
+	position := claimRunSlot(state.ExecutionId, true, now)
+	if position == 0 {
+		state.QueuedAt = 0
+		started, err := l.launch(state, nil)
+		if err != nil {
+			releaseRunSlot(state.ExecutionId)
+			return nil, err
+		}
+		return &action_kit_api.StatusResult{Completed: started.Error != nil, Error: started.Error, Messages: started.Messages}, nil
+	}
+	if waited := now.Sub(time.UnixMilli(state.QueuedAt)); waited > runQueueTimeout() {
+		releaseRunSlot(state.ExecutionId)
+		return &action_kit_api.StatusResult{Completed: true, Error: queueTimeoutError(waited)}, nil
+	}
+	return &action_kit_api.StatusResult{Messages: new(queuedMessages(state, position))}, nil
+}
+
+// statusWaiting launches a run of a synchronized start once all locations
+// arrived at the barrier, or fails it once they didn't within the timeout.
+func (l *GatlingLoadTestRunAction) statusWaiting(state *GatlingLoadTestRunState, now time.Time) (*action_kit_api.StatusResult, error) {
+	status, released, err := pollSyncStart(state, now)
+	if err != nil {
+		releaseRunSlot(state.ExecutionId)
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Error: &action_kit_api.ActionKitError{
+				Status: extutil.Ptr(action_kit_api.Errored),
+				Title:  fmt.Sprintf("Synchronized start failed: %s", err),
+			},
+		}, nil
+	}
+	if !released {
+		return &action_kit_api.StatusResult{Messages: new(waitingMessages(state, status))}, nil
+	}
+	started, err := l.launch(state, &status)
+	if err != nil {
+		releaseRunSlot(state.ExecutionId)
+		return nil, err
+	}
+	return &action_kit_api.StatusResult{Completed: started.Error != nil, Error: started.Error, Messages: started.Messages}, nil
+}
+
+// runResult derives the error and the webhook status of a run from how it
+// ended. A run stopped by an abort criterion fails with that criterion, whatever
+// exit code stopping Gatling left behind.
+func runResult(state *GatlingLoadTestRunState, exitCode int) (*action_kit_api.ActionKitError, string) {
+	switch {
+	case state.AbortedBy != "":
+		return abortError(state.AbortedBy), extwebhook.StatusFailed
+	case exitCode <= 0:
+		return nil, runStatus(exitCode)
+	case exitCode == 2:
+		return &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  "Gatling run ended with failing assertions. Reports are attached.",
+		}, runStatus(exitCode)
+	case state.OutOfMemory != "":
+		return outOfMemoryError(state.OutOfMemory), runStatus(exitCode)
+	case exitCode == 130: //130 is "killed by SIGINT" which is expected when you cancel a run
+		return nil, runStatus(exitCode)
+	default:
+		return &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Errored),
+			Title:  fmt.Sprintf("Gatling run errored, exit-code %d", exitCode),
+		}, runStatus(exitCode)
+	}
+}
+
+func (l *GatlingLoadTestRunAction) Stop(ctx context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StopResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ExecutionId, "gatling.run.stop", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExperimentExecutionId)...)
+	result, err := l.stop(ctx, state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (l *GatlingLoadTestRunAction) stop(_ context.Context, state *GatlingLoadTestRunState) (*action_kit_api.StopResult, error) {
+	defer releaseRunSlot(state.ExecutionId)
+	if state.Limits != nil && state.Limits.Cgroup != "" {
+		defer removeRunCgroup(state.Limits.Cgroup)
+	}
 	if state.CmdStateID == "" {
 		log.Info().Msg("Gatling not yet started, nothing to stop.")
 		return nil, nil
@@ -311,32 +872,24 @@ func (l *GatlingLoadTestRunAction) Stop(_ context.Context, state *GatlingLoadTes
 
 	// kill Gatling if it is still running
 	gracefulKill(state.Pid, cmdState)
+	liveRun := stopLiveRun(state.ExecutionId)
 
 	// read Stout and Stderr and send it as Messages
 	stdOut := cmdState.GetLines(true)
 	stdOutToLog(stdOut)
+	detectOutOfMemory(state, stdOut)
 	messages := stdOutToMessages(stdOut)
 
 	// read return code and send it as Message
 	exitCode := cmdState.ExitCode()
-	var resultErr *action_kit_api.ActionKitError
 	if exitCode > 0 {
 		messages = append(messages, action_kit_api.Message{
 			Level:   extutil.Ptr(action_kit_api.Error),
 			Message: fmt.Sprintf("Gatling run stopped with exit code %d", exitCode),
 		})
-		if exitCode == 2 {
-			resultErr = &action_kit_api.ActionKitError{
-				Status: extutil.Ptr(action_kit_api.Failed),
-				Title:  "Gatling run ended with failing assertions. Reports are attached.",
-			}
-		} else if exitCode != 130 { //130 is "killed by SIGINT" which is expected when you cancel a run
-			resultErr = &action_kit_api.ActionKitError{
-				Status: extutil.Ptr(action_kit_api.Errored),
-				Title:  fmt.Sprintf("Gatling run errored, exit-code %d", exitCode),
-			}
-		}
 	}
+	resultErr, status := runResult(state, exitCode)
+	defer func() { extmetrics.RunFinished(actionId, status) }()
 
 	artifacts := make([]action_kit_api.Artifact, 0)
 	executionRoot := fmt.Sprintf("/tmp/steadybit/%v", state.ExecutionId) //Folder is managed by action_kit_sdk's file download handling
@@ -346,28 +899,82 @@ func (l *GatlingLoadTestRunAction) Stop(_ context.Context, state *GatlingLoadTes
 		return nil, extension_kit.ToError("Failed to read report folder", err)
 	}
 
+	retention := reportRetention()
+
+	var reportDirs []string
 	for _, file := range files {
 		if file.IsDir() {
 			simulationLog := fmt.Sprintf("%v/%v/simulation.log", reportFolder, file.Name())
 			_, err = os.Stat(simulationLog)
 			if err == nil { // file exists
-				zippedReport := fmt.Sprintf("%v/%v.zip", reportFolder, file.Name())
-				log.Info().Msgf("Zipping report %s to %s", file.Name(), zippedReport)
-				if err := zipDir(fmt.Sprintf("%v/%v", reportFolder, file.Name()), zippedReport); err != nil {
-					return nil, extension_kit.ToError("Failed to zip report", err)
-				}
-				content, err := extfile.File2Base64(zippedReport)
-				if err != nil {
-					return nil, err
-				}
-				artifacts = append(artifacts, action_kit_api.Artifact{
-					// Gatling names the report folder "<simulation>-<timestamp>";
-					// including it keeps the artifacts of a run that produced
-					// several reports apart -- they would otherwise all be
-					// attached under the same label.
-					Label: fmt.Sprintf("$(experimentKey)_$(executionId)_%s_report.zip", file.Name()),
-					Data:  content,
-				})
+				reportDirs = append(reportDirs, fmt.Sprintf("%v/%v", reportFolder, file.Name()))
+			}
+		}
+	}
+
+	// SLOs are evaluated on complete runs only, not on ones stopped or errored
+	if state.Slos != nil && (exitCode == 0 || exitCode == 2) {
+		sloMessages, sloErr := checkSlos(state.Slos, reportDirs)
+		messages = append(messages, sloMessages...)
+		if sloErr != nil && resultErr == nil {
+			resultErr = sloErr
+			status = extwebhook.StatusFailed
+			if *sloErr.Status == action_kit_api.Errored {
+				status = extwebhook.StatusErrored
+			}
+		}
+	}
+
+	// baselines are compared and saved for complete runs only as well
+	if state.Baseline != nil && (exitCode == 0 || exitCode == 2) {
+		baselineMessages, baselineErr := checkBaseline(state, readSimulationSummaries(reportDirs))
+		messages = append(messages, baselineMessages...)
+		if baselineErr != nil && resultErr == nil {
+			resultErr = baselineErr
+			status = extwebhook.StatusFailed
+		}
+	}
+
+	messages = append(messages, summaryMessages(reportDirs)...)
+	analyses := analyzeReports(reportDirs, state.AttackPhase, state.CsvExport)
+	messages = append(messages, breakdownMessages(reportDirs, analyses)...)
+	phases, phaseMessages := phaseStatistics(reportDirs, analyses)
+	messages = append(messages, phaseMessages...)
+
+	// read before the reports are moved away by retainReport
+	notifyWebhooks(state, exitCode, reportDirs, status, resultErr)
+	exportFinalOtlpMetrics(liveRun, state, reportDirs)
+
+	uploadCtx, cancelUploads := context.WithTimeout(context.Background(), uploadBudget)
+	defer cancelUploads()
+	for _, reportDir := range reportDirs {
+		name := filepath.Base(reportDir)
+		attached, warnings, err := reportArtifacts(reportDir, state.ArtifactMode, phases[reportDir])
+		if err != nil {
+			return nil, extension_kit.ToError("Failed to attach report", err)
+		}
+		artifacts = append(artifacts, attached...)
+		messages = append(messages, warnings...)
+		if state.CsvExport {
+			attached, warnings = csvArtifacts(reportDir, analyses[reportDir])
+			artifacts = append(artifacts, attached...)
+			messages = append(messages, warnings...)
+		}
+
+		if extstorage.Enabled() {
+			messages = append(messages, uploadReport(uploadCtx, state, reportDir)...)
+		}
+
+		if config.Config.AggregationUrl != "" {
+			messages = append(messages, pushSimulationLog(uploadCtx, state, reportDir)...)
+		}
+
+		if retention > 0 && hasHtmlReport(reportDir) {
+			path, err := retainReport(state.ExecutionId, reportDir)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Failed to keep report %s", name)
+			} else {
+				messages = append(messages, reportMessage(name, path))
 			}
 		}
 	}
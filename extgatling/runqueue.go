@@ -0,0 +1,211 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extcmd"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// queueStale is how long a queued run may go without asking for its slot
+// before it is dropped from the queue, so an abandoned run doesn't block the
+// ones behind it.
+const queueStale = time.Minute
+
+// slotStale is how long a run may go without a status call before its slot
+// is freed, so a run whose Stop never arrives doesn't block the location.
+// Runs get a status call every 5s.
+const slotStale = 2 * time.Minute
+
+// runSlots are the local runs that may run Gatling, from their Prepare until
+// they are stopped, runQueue are the runs waiting for a slot, first in first
+// out.
+var (
+	runSlotsMu sync.Mutex
+	runSlots   = map[uuid.UUID]*runSlot{}
+	runQueue   []queuedRun
+)
+
+type runSlot struct {
+	// when the run claimed the slot or last had a status call
+	seen time.Time
+	// whether the run reserved the slot in Prepare, which is still going
+	preparing bool
+	// the command of the run, once Gatling was started
+	cmdStateId string
+}
+
+type queuedRun struct {
+	id   uuid.UUID
+	seen time.Time
+}
+
+// runQueueTimeout is how long a run waits for a free slot, zero if runs are
+// rejected when the location is busy.
+func runQueueTimeout() time.Duration {
+	return config.Config.RunQueueTimeout
+}
+
+// expireRunSlots frees the slots of runs whose Gatling exited or that had no
+// status call for slotStale. The caller holds runSlotsMu.
+func expireRunSlots(now time.Time) {
+	for id, slot := range runSlots {
+		if !slot.preparing && now.Sub(slot.seen) > slotStale {
+			log.Warn().Msgf("Freeing the slot of run %s, it had no status call for %s", id, now.Sub(slot.seen).Round(time.Second))
+			delete(runSlots, id)
+		} else if slot.cmdStateId != "" && commandExited(slot.cmdStateId) {
+			log.Info().Msgf("Freeing the slot of run %s, Gatling exited", id)
+			delete(runSlots, id)
+		}
+	}
+}
+
+func commandExited(cmdStateId string) bool {
+	cmdState, err := extcmd.GetCmdState(cmdStateId)
+	return err != nil || cmdState.ExitCode() != -1
+}
+
+// runCounts returns how many local runs hold a slot, including the ones
+// still preparing, and how many wait for one, for the location attributes.
+func runCounts(now time.Time) (active, queued int) {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	expireRunSlots(now)
+	for _, run := range runQueue {
+		if now.Sub(run.seen) <= queueStale {
+			queued++
+		}
+	}
+	return len(runSlots), queued
+}
+
+// busyError returns an error if the location runs as many runs as it may,
+// nil if there is a free slot.
+func busyError() error {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	expireRunSlots(time.Now())
+	if limit := config.Config.MaxConcurrentRuns; limit > 0 && len(runSlots)+len(runQueue) >= limit {
+		return fmt.Errorf("%d of %d concurrent runs are active", len(runSlots), limit)
+	}
+	return nil
+}
+
+// claimRunSlot takes a slot for the run and returns 0 if one is free and no
+// run queued before it. Otherwise it queues the run, if queue is set, and
+// returns its position, or returns -1.
+func claimRunSlot(id uuid.UUID, queue bool, now time.Time) int {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	if slot, claimed := runSlots[id]; claimed {
+		slot.seen = now
+		return 0
+	}
+	expireRunSlots(now)
+	runQueue = slices.DeleteFunc(runQueue, func(queued queuedRun) bool {
+		return queued.id != id && now.Sub(queued.seen) > queueStale
+	})
+	index := slices.IndexFunc(runQueue, func(queued queuedRun) bool { return queued.id == id })
+	limit := config.Config.MaxConcurrentRuns
+	if (limit <= 0 || len(runSlots) < limit) && (index == 0 || index < 0 && len(runQueue) == 0) {
+		if index == 0 {
+			runQueue = runQueue[1:]
+		}
+		runSlots[id] = &runSlot{seen: now}
+		return 0
+	}
+	if index >= 0 {
+		runQueue[index].seen = now
+		return index + 1
+	}
+	if !queue {
+		return -1
+	}
+	runQueue = append(runQueue, queuedRun{id: id, seen: now})
+	return len(runQueue)
+}
+
+// reserveRunSlot claims a slot for a run in Prepare, like claimRunSlot, so
+// the work of Prepare already counts against the limit. Start takes the slot
+// over.
+func reserveRunSlot(id uuid.UUID, queue bool, now time.Time) int {
+	position := claimRunSlot(id, queue, now)
+	if position == 0 {
+		runSlotsMu.Lock()
+		defer runSlotsMu.Unlock()
+		if slot, claimed := runSlots[id]; claimed {
+			slot.preparing = true
+		}
+	}
+	return position
+}
+
+// runSlotPrepared ends the reservation of Prepare, from now on the slot
+// expires if the run gets no Start or status calls.
+func runSlotPrepared(id uuid.UUID, now time.Time) {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	if slot, claimed := runSlots[id]; claimed {
+		slot.preparing = false
+		slot.seen = now
+	}
+}
+
+// refreshRunSlot keeps the slot of a run on its status calls.
+func refreshRunSlot(id uuid.UUID, now time.Time) {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	if slot, claimed := runSlots[id]; claimed {
+		slot.seen = now
+	}
+}
+
+// runSlotStarted tells the slot of a run which command runs Gatling, so the
+// slot is freed once it exits.
+func runSlotStarted(id uuid.UUID, cmdStateId string) {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	if slot, claimed := runSlots[id]; claimed {
+		slot.cmdStateId = cmdStateId
+	}
+}
+
+// releaseRunSlot frees the slot of the run, or removes it from the queue.
+func releaseRunSlot(id uuid.UUID) {
+	runSlotsMu.Lock()
+	defer runSlotsMu.Unlock()
+	delete(runSlots, id)
+	runQueue = slices.DeleteFunc(runQueue, func(queued queuedRun) bool { return queued.id == id })
+}
+
+// queuedMessages tell the position of a queued run, only when it changed.
+func queuedMessages(state *GatlingLoadTestRunState, position int) []action_kit_api.Message {
+	if position == state.QueuePosition {
+		return nil
+	}
+	state.QueuePosition = position
+	text := fmt.Sprintf("Waiting for a free slot, position %d in the queue of this location", position)
+	return []action_kit_api.Message{
+		{Level: extutil.Ptr(action_kit_api.Info), Message: text},
+		markdownMessage("⏸️ " + text),
+	}
+}
+
+// queueTimeoutError is the error of a run that didn't get a slot in time.
+func queueTimeoutError(waited time.Duration) *action_kit_api.ActionKitError {
+	return &action_kit_api.ActionKitError{
+		Status: extutil.Ptr(action_kit_api.Errored),
+		Title:  fmt.Sprintf("Gatling run waited %s for a free slot on the location, the limit of %d concurrent runs is still reached.", waited.Round(time.Second), config.Config.MaxConcurrentRuns),
+	}
+}
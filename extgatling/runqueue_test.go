@@ -0,0 +1,178 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extcmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_claimRunSlot_queues_runs_first_in_first_out(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	assert.Equal(t, 0, claimRunSlot(first, true, now))
+	assert.Equal(t, 1, claimRunSlot(second, true, now))
+	assert.Equal(t, 2, claimRunSlot(third, true, now))
+	assert.Equal(t, 2, claimRunSlot(third, true, now))
+	assert.Error(t, busyError())
+
+	releaseRunSlot(first)
+	assert.Equal(t, 2, claimRunSlot(third, true, now), "the slot is for the first in the queue")
+	assert.Equal(t, 0, claimRunSlot(second, true, now))
+	assert.Equal(t, 1, claimRunSlot(third, true, now))
+
+	releaseRunSlot(second)
+	assert.Equal(t, 0, claimRunSlot(third, true, now))
+	releaseRunSlot(third)
+	assert.NoError(t, busyError())
+}
+
+func Test_claimRunSlot_rejects_without_queue(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	first, second := uuid.New(), uuid.New()
+
+	assert.Equal(t, 0, claimRunSlot(first, false, time.Now()))
+	assert.Equal(t, -1, claimRunSlot(second, false, time.Now()))
+
+	releaseRunSlot(first)
+	assert.Equal(t, 0, claimRunSlot(second, false, time.Now()))
+	releaseRunSlot(second)
+}
+
+func Test_claimRunSlot_drops_abandoned_runs_from_the_queue(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	running, abandoned, waiting := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	claimRunSlot(running, true, now)
+	claimRunSlot(abandoned, true, now)
+	claimRunSlot(waiting, true, now)
+	releaseRunSlot(running)
+
+	assert.Equal(t, 0, claimRunSlot(waiting, true, now.Add(queueStale+time.Second)))
+	releaseRunSlot(waiting)
+}
+
+func Test_claimRunSlot_frees_stale_slots(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	abandoned, waiting := uuid.New(), uuid.New()
+	now := time.Now()
+	claimRunSlot(abandoned, true, now)
+	assert.Equal(t, 1, claimRunSlot(waiting, true, now))
+
+	refreshRunSlot(abandoned, now.Add(slotStale))
+	assert.Equal(t, 1, claimRunSlot(waiting, true, now.Add(slotStale+time.Second)), "refreshed by a status call")
+	assert.Equal(t, 0, claimRunSlot(waiting, true, now.Add(2*slotStale+2*time.Second)))
+	releaseRunSlot(waiting)
+}
+
+func Test_claimRunSlot_frees_the_slot_once_gatling_exited(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	running, waiting := uuid.New(), uuid.New()
+	now := time.Now()
+	claimRunSlot(running, true, now)
+	cmd := exec.Command("true")
+	cmdState := extcmd.NewCmdState(cmd)
+	defer extcmd.RemoveCmdState(cmdState.Id)
+	require.NoError(t, cmd.Start())
+	runSlotStarted(running, cmdState.Id)
+	assert.Equal(t, 1, claimRunSlot(waiting, true, now), "still running")
+
+	require.NoError(t, cmdState.Wait())
+	assert.Equal(t, 0, claimRunSlot(waiting, true, now))
+	releaseRunSlot(waiting)
+}
+
+func Test_reserveRunSlot_in_prepare_counts_against_the_limit(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	preparing, other := uuid.New(), uuid.New()
+	now := time.Now()
+
+	assert.Equal(t, 0, reserveRunSlot(preparing, false, now))
+	assert.Equal(t, -1, reserveRunSlot(other, false, now), "a second Prepare is rejected")
+	assert.Equal(t, -1, claimRunSlot(other, false, now.Add(2*slotStale)), "a long Prepare keeps its slot")
+
+	runSlotPrepared(preparing, now.Add(2*slotStale))
+	assert.Equal(t, 0, claimRunSlot(preparing, false, now.Add(2*slotStale)), "Start takes the slot over")
+	assert.Equal(t, -1, claimRunSlot(other, false, now.Add(2*slotStale)))
+
+	releaseRunSlot(preparing)
+	assert.Equal(t, 0, reserveRunSlot(other, false, now.Add(2*slotStale)))
+}
+
+func Test_Prepare_releases_the_reserved_slot_when_it_fails(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	request := action_kit_api.PrepareActionRequestBody{
+		ExecutionId: uuid.New(),
+		Config:      map[string]any{"simulation": "TestSimulation"},
+		ExecutionContext: &action_kit_api.ExecutionContext{
+			ExperimentKey: new("ADM-1"),
+			ExecutionId:   new(42),
+		},
+	}
+
+	_, err := (&GatlingLoadTestRunAction{}).Prepare(context.Background(), &GatlingLoadTestRunState{}, request)
+
+	require.ErrorContains(t, err, "report folder", "fails after reserving, there is no execution folder")
+	assert.Empty(t, runSlots)
+}
+
+func Test_statusQueued_fails_after_the_queue_timeout(t *testing.T) {
+	limitConcurrentRuns(t, 1)
+	config.Config.RunQueueTimeout = time.Minute
+	defer func() { config.Config.RunQueueTimeout = 0 }()
+	running := uuid.New()
+	claimRunSlot(running, true, time.Now())
+	defer releaseRunSlot(running)
+	now := time.Now()
+	state := &GatlingLoadTestRunState{ExecutionId: uuid.New(), QueuedAt: now.UnixMilli()}
+	claimRunSlot(state.ExecutionId, true, now)
+
+	result, err := (&GatlingLoadTestRunAction{}).statusQueued(state, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.False(t, result.Completed)
+
+	result, err = (&GatlingLoadTestRunAction{}).statusQueued(state, now.Add(61*time.Second))
+	require.NoError(t, err)
+	assert.True(t, result.Completed)
+	assert.Equal(t, action_kit_api.Errored, *result.Error.Status)
+	assert.Empty(t, runQueue)
+}
+
+func Test_queuedMessages_only_when_the_position_changed(t *testing.T) {
+	state := &GatlingLoadTestRunState{}
+
+	assert.Len(t, queuedMessages(state, 2), 2)
+	assert.Empty(t, queuedMessages(state, 2))
+	messages := queuedMessages(state, 1)
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0].Message, "position 1")
+}
+
+func limitConcurrentRuns(t *testing.T, limit int) {
+	t.Helper()
+	config.Config.MaxConcurrentRuns = limit
+	resetRunSlots := func() {
+		runSlotsMu.Lock()
+		runSlots = map[uuid.UUID]*runSlot{}
+		runQueue = nil
+		runSlotsMu.Unlock()
+	}
+	resetRunSlots()
+	t.Cleanup(func() {
+		config.Config.MaxConcurrentRuns = 0
+		resetRunSlots()
+	})
+}
@@ -0,0 +1,439 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+)
+
+// Record headers of the binary simulation.log written by Gatling 3.11 and
+// later.
+const (
+	recordRun     byte = 0
+	recordRequest byte = 1
+	recordUser    byte = 2
+	recordGroup   byte = 3
+	recordError   byte = 4
+)
+
+// errIncompleteRecord signals that the record at hand isn't completely written
+// yet, reading has to continue once Gatling appended more.
+var errIncompleteRecord = errors.New("incomplete record")
+
+// simulationLogRun is the header of a simulation.log.
+type simulationLogRun struct {
+	GatlingVersion string
+	Simulation     string
+	Start          int64 // epoch millis
+	Description    string
+	Scenarios      []string
+}
+
+// requestRecord is one request. Timestamps are epoch millis.
+type requestRecord struct {
+	Groups  []string
+	Name    string
+	Start   int64
+	End     int64
+	Ok      bool
+	Message string
+}
+
+// userRecord is the start or end of a virtual user.
+type userRecord struct {
+	Scenario  string
+	Start     bool
+	Timestamp int64
+}
+
+// groupRecord is the completion of a group.
+type groupRecord struct {
+	Groups                []string
+	Start                 int64
+	End                   int64
+	CumulatedResponseTime int32
+	Ok                    bool
+}
+
+// errorRecord is an error that crashed a virtual user, e.g. a failed session
+// expression.
+type errorRecord struct {
+	Message   string
+	Timestamp int64
+}
+
+// simulationLogReader reads the records of a simulation.log while Gatling
+// is still writing it. The timestamps in the records are relative to the start
+// of the run, the reader turns them into epoch millis.
+type simulationLogReader struct {
+	file    *os.File
+	pending []byte
+	run     *simulationLogRun
+	strings map[int32]string
+}
+
+func openSimulationLog(path string) (*simulationLogReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &simulationLogReader{file: file, strings: map[int32]string{}}, nil
+}
+
+// runHeaderLimit is how much of a simulation.log readRunStart reads, way more
+// than a run header takes.
+const runHeaderLimit = 64 * 1024
+
+// readRunStart returns the start of the run from the header of a
+// simulation.log, epoch millis, zero if Gatling didn't write it completely yet.
+func readRunStart(path string) (int64, error) {
+	reader, err := openSimulationLog(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+	header := make([]byte, runHeaderLimit)
+	n, err := io.ReadFull(reader.file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	record, err := reader.decodeRecord(&recordDecoder{data: header[:n], strings: reader.strings})
+	if errors.Is(err, errIncompleteRecord) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return record.(*simulationLogRun).Start, nil
+}
+
+func (r *simulationLogReader) Close() error {
+	return r.file.Close()
+}
+
+// readAvailable reads the records Gatling appended since the last call and
+// hands them to visit, one of *simulationLogRun, *requestRecord, *userRecord,
+// *groupRecord and *errorRecord. The records are decoded chunk by chunk, so
+// only the one at hand is held in memory. A record that isn't complete yet is
+// kept for the next call.
+func (r *simulationLogReader) readAvailable(visit func(record any)) error {
+	chunk := make([]byte, 64*1024)
+	for {
+		n, err := r.file.Read(chunk)
+		r.pending = append(r.pending, chunk[:n]...)
+		if decodeErr := r.decodePending(visit); decodeErr != nil {
+			return decodeErr
+		}
+		if err == io.EOF || n == 0 {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// decodePending hands the complete records read so far to visit and keeps the
+// rest.
+func (r *simulationLogReader) decodePending(visit func(record any)) error {
+	pos := 0
+	for pos < len(r.pending) {
+		d := &recordDecoder{data: r.pending[pos:], strings: r.strings}
+		record, err := r.decodeRecord(d)
+		if errors.Is(err, errIncompleteRecord) {
+			// strings cached while decoding the incomplete record are decoded
+			// again next time, overwriting them is harmless
+			break
+		} else if err != nil {
+			return err
+		}
+		pos += d.pos
+		visit(record)
+	}
+	r.pending = append(r.pending[:0], r.pending[pos:]...)
+	return nil
+}
+
+func (r *simulationLogReader) decodeRecord(d *recordDecoder) (any, error) {
+	header, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	if header != recordRun && r.run == nil {
+		return nil, fmt.Errorf("expected the run header, got record type %d", header)
+	}
+	switch header {
+	case recordRun:
+		run, err := d.run()
+		if err != nil {
+			return nil, err
+		}
+		r.run = run
+		return run, nil
+	case recordRequest:
+		var rec requestRecord
+		if rec.Groups, err = d.groups(); err != nil {
+			return nil, err
+		}
+		if rec.Name, err = d.cachedString(); err != nil {
+			return nil, err
+		}
+		start, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		end, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		if rec.Ok, err = d.bool(); err != nil {
+			return nil, err
+		}
+		if rec.Message, err = d.cachedString(); err != nil {
+			return nil, err
+		}
+		rec.Start = r.run.Start + int64(start)
+		rec.End = r.run.Start + int64(end)
+		return &rec, nil
+	case recordUser:
+		scenario, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		start, err := d.bool()
+		if err != nil {
+			return nil, err
+		}
+		timestamp, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		rec := userRecord{Start: start, Timestamp: r.run.Start + int64(timestamp)}
+		if scenario >= 0 && int(scenario) < len(r.run.Scenarios) {
+			rec.Scenario = r.run.Scenarios[scenario]
+		}
+		return &rec, nil
+	case recordGroup:
+		var rec groupRecord
+		if rec.Groups, err = d.groups(); err != nil {
+			return nil, err
+		}
+		start, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		end, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		if rec.CumulatedResponseTime, err = d.int32(); err != nil {
+			return nil, err
+		}
+		if rec.Ok, err = d.bool(); err != nil {
+			return nil, err
+		}
+		rec.Start = r.run.Start + int64(start)
+		rec.End = r.run.Start + int64(end)
+		return &rec, nil
+	case recordError:
+		message, err := d.cachedString()
+		if err != nil {
+			return nil, err
+		}
+		timestamp, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		return &errorRecord{Message: message, Timestamp: r.run.Start + int64(timestamp)}, nil
+	default:
+		return nil, fmt.Errorf("unknown record type %d", header)
+	}
+}
+
+// recordDecoder decodes the big-endian primitives Gatling writes with a
+// java.nio.ByteBuffer.
+type recordDecoder struct {
+	data    []byte
+	pos     int
+	strings map[int32]string
+}
+
+func (d *recordDecoder) take(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+	if d.pos+n > len(d.data) {
+		return nil, errIncompleteRecord
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *recordDecoder) byte() (byte, error) {
+	b, err := d.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *recordDecoder) bool() (bool, error) {
+	b, err := d.byte()
+	return b != 0, err
+}
+
+func (d *recordDecoder) int32() (int32, error) {
+	b, err := d.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *recordDecoder) int64() (int64, error) {
+	b, err := d.take(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// string decodes a string the way Gatling writes it: the length of the bytes
+// of the Java string, the bytes and the coder of the string, which is 0 for
+// Latin-1 and 1 for UTF-16. Empty strings are just a zero length.
+func (d *recordDecoder) string() (string, error) {
+	length, err := d.int32()
+	if err != nil {
+		return "", err
+	}
+	if length == 0 {
+		return "", nil
+	}
+	b, err := d.take(int(length))
+	if err != nil {
+		return "", err
+	}
+	coder, err := d.byte()
+	if err != nil {
+		return "", err
+	}
+	if coder == 0 {
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes), nil
+	}
+	// the UTF-16 bytes of a Java string are in the byte order of the platform,
+	// which is little-endian on all platforms Gatling runs on in practice
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// cachedString decodes a string that is written in full only the first time
+// it occurs: a non-negative index followed by the string defines it, a
+// negative index refers to a string defined earlier.
+func (d *recordDecoder) cachedString() (string, error) {
+	index, err := d.int32()
+	if err != nil {
+		return "", err
+	}
+	if index < 0 {
+		s, ok := d.strings[-index]
+		if !ok {
+			return "", fmt.Errorf("unknown cached string %d", -index)
+		}
+		return s, nil
+	}
+	s, err := d.string()
+	if err != nil {
+		return "", err
+	}
+	d.strings[index] = s
+	return s, nil
+}
+
+// count decodes the number of elements that follow. Every element takes at
+// least a byte, so a count beyond the remaining bytes can't be complete yet,
+// which also keeps a corrupt count from preallocating the memory for it.
+func (d *recordDecoder) count(what string) (int, error) {
+	count, err := d.int32()
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("invalid %s count %d", what, count)
+	}
+	if int(count) > len(d.data)-d.pos {
+		return 0, errIncompleteRecord
+	}
+	return int(count), nil
+}
+
+func (d *recordDecoder) groups() ([]string, error) {
+	count, err := d.count("group")
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, 0, count)
+	for range count {
+		group, err := d.cachedString()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (d *recordDecoder) run() (*simulationLogRun, error) {
+	var run simulationLogRun
+	var err error
+	if run.GatlingVersion, err = d.string(); err != nil {
+		return nil, err
+	}
+	if run.Simulation, err = d.string(); err != nil {
+		return nil, err
+	}
+	if run.Start, err = d.int64(); err != nil {
+		return nil, err
+	}
+	if run.Description, err = d.string(); err != nil {
+		return nil, err
+	}
+	scenarios, err := d.count("scenario")
+	if err != nil {
+		return nil, err
+	}
+	for range scenarios {
+		scenario, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		run.Scenarios = append(run.Scenarios, scenario)
+	}
+	assertions, err := d.count("assertion")
+	if err != nil {
+		return nil, err
+	}
+	for range assertions {
+		// assertions are serialized Java objects we have no use for
+		length, err := d.int32()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := d.take(int(length)); err != nil {
+			return nil, err
+		}
+	}
+	return &run, nil
+}
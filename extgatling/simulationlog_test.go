@@ -0,0 +1,218 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simulationLogWriter writes a simulation.log the way Gatling does.
+type simulationLogWriter struct {
+	buf     bytes.Buffer
+	start   int64
+	strings map[string]int32
+}
+
+func newSimulationLogWriter(simulation string, start int64, scenarios ...string) *simulationLogWriter {
+	w := &simulationLogWriter{start: start, strings: map[string]int32{}}
+	w.buf.WriteByte(recordRun)
+	w.string("3.15.1")
+	w.string(simulation)
+	w.int64(start)
+	w.string("")
+	w.int32(int32(len(scenarios)))
+	for _, scenario := range scenarios {
+		w.string(scenario)
+	}
+	w.int32(0)
+	return w
+}
+
+func (w *simulationLogWriter) user(scenario int32, start bool, timestamp int64) *simulationLogWriter {
+	w.buf.WriteByte(recordUser)
+	w.int32(scenario)
+	w.bool(start)
+	w.int32(int32(timestamp - w.start))
+	return w
+}
+
+func (w *simulationLogWriter) request(groups []string, name string, start, end int64, ok bool, message string) *simulationLogWriter {
+	w.buf.WriteByte(recordRequest)
+	w.groups(groups)
+	w.cachedString(name)
+	w.int32(int32(start - w.start))
+	w.int32(int32(end - w.start))
+	w.bool(ok)
+	w.cachedString(message)
+	return w
+}
+
+func (w *simulationLogWriter) group(groups []string, start, end int64, cumulated int32, ok bool) *simulationLogWriter {
+	w.buf.WriteByte(recordGroup)
+	w.groups(groups)
+	w.int32(int32(start - w.start))
+	w.int32(int32(end - w.start))
+	w.int32(cumulated)
+	w.bool(ok)
+	return w
+}
+
+func (w *simulationLogWriter) error(message string, timestamp int64) *simulationLogWriter {
+	w.buf.WriteByte(recordError)
+	w.cachedString(message)
+	w.int32(int32(timestamp - w.start))
+	return w
+}
+
+func (w *simulationLogWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func (w *simulationLogWriter) int32(v int32) {
+	_ = binary.Write(&w.buf, binary.BigEndian, v)
+}
+
+func (w *simulationLogWriter) int64(v int64) {
+	_ = binary.Write(&w.buf, binary.BigEndian, v)
+}
+
+func (w *simulationLogWriter) bool(v bool) {
+	if v {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+}
+
+// string writes ASCII strings only, as Latin-1.
+func (w *simulationLogWriter) string(s string) {
+	w.int32(int32(len(s)))
+	if s != "" {
+		w.buf.WriteString(s)
+		w.buf.WriteByte(0)
+	}
+}
+
+func (w *simulationLogWriter) cachedString(s string) {
+	if index, ok := w.strings[s]; ok {
+		w.int32(-index)
+		return
+	}
+	index := int32(len(w.strings) + 1)
+	w.strings[s] = index
+	w.int32(index)
+	w.string(s)
+}
+
+func (w *simulationLogWriter) groups(groups []string) {
+	w.int32(int32(len(groups)))
+	for _, group := range groups {
+		w.cachedString(group)
+	}
+}
+
+func Test_simulationLogReader_reads_all_record_types(t *testing.T) {
+	const start = 1767268800000
+	content := newSimulationLogWriter("computerdatabase.BasicSimulation", start, "Users").
+		user(0, true, start+10).
+		request(nil, "home", start+20, start+45, true, "").
+		request([]string{"checkout"}, "pay", start+50, start+150, false, "status.find.is(200), but actually found 500").
+		group([]string{"checkout"}, start+50, start+150, 100, false).
+		request(nil, "home", start+160, start+170, true, "").
+		error("crashed", start+180).
+		user(0, false, start+200).
+		bytes()
+	path := filepath.Join(t.TempDir(), "simulation.log")
+	require.NoError(t, os.WriteFile(path, content, 0644))
+	reader, err := openSimulationLog(path)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+
+	var records []any
+	require.NoError(t, reader.readAvailable(func(record any) { records = append(records, record) }))
+
+	require.Len(t, records, 8)
+	assert.Equal(t, &simulationLogRun{GatlingVersion: "3.15.1", Simulation: "computerdatabase.BasicSimulation", Start: start, Scenarios: []string{"Users"}}, records[0])
+	assert.Equal(t, &userRecord{Scenario: "Users", Start: true, Timestamp: start + 10}, records[1])
+	assert.Equal(t, &requestRecord{Groups: []string{}, Name: "home", Start: start + 20, End: start + 45, Ok: true}, records[2])
+	assert.Equal(t, &requestRecord{Groups: []string{"checkout"}, Name: "pay", Start: start + 50, End: start + 150, Ok: false, Message: "status.find.is(200), but actually found 500"}, records[3])
+	assert.Equal(t, &groupRecord{Groups: []string{"checkout"}, Start: start + 50, End: start + 150, CumulatedResponseTime: 100, Ok: false}, records[4])
+	assert.Equal(t, &requestRecord{Groups: []string{}, Name: "home", Start: start + 160, End: start + 170, Ok: true}, records[5])
+	assert.Equal(t, &errorRecord{Message: "crashed", Timestamp: start + 180}, records[6])
+	assert.Equal(t, &userRecord{Scenario: "Users", Start: false, Timestamp: start + 200}, records[7])
+}
+
+func Test_simulationLogReader_continues_an_incomplete_record(t *testing.T) {
+	const start = 1767268800000
+	content := newSimulationLogWriter("BasicSimulation", start, "Users").
+		request(nil, "home", start+20, start+45, true, "").
+		request(nil, "home", start+50, start+60, true, "").
+		bytes()
+	path := filepath.Join(t.TempDir(), "simulation.log")
+	cut := len(content) - 5
+	require.NoError(t, os.WriteFile(path, content[:cut], 0644))
+	reader, err := openSimulationLog(path)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+
+	var records []any
+	visit := func(record any) { records = append(records, record) }
+	require.NoError(t, reader.readAvailable(visit))
+	assert.Len(t, records, 2)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.Write(content[cut:])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, reader.readAvailable(visit))
+	require.Len(t, records, 3)
+	assert.Equal(t, &requestRecord{Groups: []string{}, Name: "home", Start: start + 50, End: start + 60, Ok: true}, records[2])
+}
+
+func Test_readRunStart(t *testing.T) {
+	dir := t.TempDir()
+	content := newSimulationLogWriter("BasicSimulation", 1767268800000, "scenario").user(0, true, 5).bytes()
+	writeFile(t, filepath.Join(dir, "complete.log"), string(content))
+	writeFile(t, filepath.Join(dir, "incomplete.log"), string(content[:10]))
+
+	start, err := readRunStart(filepath.Join(dir, "complete.log"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1767268800000), start)
+
+	start, err = readRunStart(filepath.Join(dir, "incomplete.log"))
+	require.NoError(t, err)
+	assert.Zero(t, start)
+}
+
+func Test_recordDecoder_bounds_the_counts_by_the_remaining_bytes(t *testing.T) {
+	w := &simulationLogWriter{strings: map[string]int32{}}
+	w.int32(1 << 30)
+	w.cachedString("group")
+	_, err := (&recordDecoder{data: w.bytes(), strings: map[int32]string{}}).groups()
+	assert.ErrorIs(t, err, errIncompleteRecord)
+
+	w = &simulationLogWriter{strings: map[string]int32{}}
+	w.int32(-1)
+	_, err = (&recordDecoder{data: w.bytes(), strings: map[int32]string{}}).groups()
+	assert.EqualError(t, err, "invalid group count -1")
+
+	w = &simulationLogWriter{strings: map[string]int32{}}
+	w.string("3.15.1")
+	w.string("computerdatabase.BasicSimulation")
+	w.int64(1767268800000)
+	w.string("")
+	w.int32(1 << 30)
+	_, err = (&recordDecoder{data: w.bytes(), strings: map[int32]string{}}).run()
+	assert.ErrorIs(t, err, errIncompleteRecord)
+}
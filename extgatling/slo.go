@@ -0,0 +1,249 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// sloThresholds are the thresholds of a request, or of all requests of a
+// simulation. Unset thresholds are nil, as zero is a valid threshold for the
+// error percentage.
+type sloThresholds struct {
+	MaxP95             *float64 `json:"maxP95,omitempty"` // milliseconds
+	MaxP99             *float64 `json:"maxP99,omitempty"` // milliseconds
+	MaxErrorPercentage *float64 `json:"maxErrorPercentage,omitempty"`
+	MinThroughput      *float64 `json:"minThroughput,omitempty"` // requests per second
+}
+
+// slos are the thresholds the results of a local run are evaluated against.
+type slos struct {
+	Global     sloThresholds            `json:"global"`
+	PerRequest map[string]sloThresholds `json:"perRequest,omitempty"`
+}
+
+func (t sloThresholds) isEmpty() bool {
+	return t.MaxP95 == nil && t.MaxP99 == nil && t.MaxErrorPercentage == nil && t.MinThroughput == nil
+}
+
+// parseSlos builds the SLOs from the parameters of the action, nil if none is
+// set.
+func parseSlos(config GatlingLoadTestRunConfig) (*slos, error) {
+	result := &slos{
+		Global: sloThresholds{
+			MaxP95:             durationMillis(config.SloMaxP95),
+			MaxP99:             durationMillis(config.SloMaxP99),
+			MaxErrorPercentage: config.SloMaxErrorPercentage,
+			MinThroughput:      config.SloMinThroughput,
+		},
+	}
+	for _, entry := range config.SloPerRequest {
+		name := strings.TrimSpace(entry["key"])
+		if name == "" {
+			continue
+		}
+		thresholds, err := parseSloThresholds(entry["value"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLOs of request %q: %w", name, err)
+		}
+		if result.PerRequest == nil {
+			result.PerRequest = map[string]sloThresholds{}
+		}
+		result.PerRequest[name] = thresholds
+	}
+	if result.Global.isEmpty() && len(result.PerRequest) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func durationMillis(millis *int) *float64 {
+	if millis == nil || *millis <= 0 {
+		return nil
+	}
+	return new(float64(*millis))
+}
+
+// parseSloThresholds parses thresholds like "p95=500ms, p99=1s, errors=1,
+// throughput=10". Response times without unit are milliseconds.
+func parseSloThresholds(spec string) (sloThresholds, error) {
+	var thresholds sloThresholds
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return thresholds, fmt.Errorf("expected <threshold>=<value>, got %q", part)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "p95":
+			thresholds.MaxP95, err = parseResponseTime(value)
+		case "p99":
+			thresholds.MaxP99, err = parseResponseTime(value)
+		case "errors":
+			thresholds.MaxErrorPercentage, err = parseNumber(strings.TrimSuffix(value, "%"))
+		case "throughput":
+			thresholds.MinThroughput, err = parseNumber(value)
+		default:
+			return thresholds, fmt.Errorf("unknown threshold %q, expected p95, p99, errors or throughput", key)
+		}
+		if err != nil {
+			return thresholds, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+	}
+	if thresholds.isEmpty() {
+		return thresholds, fmt.Errorf("no threshold set")
+	}
+	return thresholds, nil
+}
+
+func parseResponseTime(value string) (*float64, error) {
+	if millis, err := strconv.ParseFloat(value, 64); err == nil {
+		return &millis, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(float64(d) / float64(time.Millisecond)), nil
+}
+
+func parseNumber(value string) (*float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
+
+// sloResult is the outcome of evaluating one threshold.
+type sloResult struct {
+	Message  string
+	Violated bool
+}
+
+// evaluateSlos evaluates the SLOs against the statistics of the run's reports.
+// Requests with thresholds that aren't in a report are reported as violations,
+// likely their name is misspelled.
+func evaluateSlos(slos *slos, summaries []*simulationSummary) []sloResult {
+	var results []sloResult
+	for _, summary := range summaries {
+		prefix := ""
+		if len(summaries) > 1 {
+			prefix = summary.Simulation + ": "
+		}
+		results = append(results, evaluateThresholds(prefix+"all requests", slos.Global, summary.Global)...)
+
+		requests := make(map[string]requestSummary, len(summary.Requests))
+		for _, request := range summary.Requests {
+			requests[request.Name] = request
+		}
+		names := make([]string, 0, len(slos.PerRequest))
+		for name := range slos.PerRequest {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			request, ok := requests[name]
+			if !ok {
+				results = append(results, sloResult{
+					Message:  fmt.Sprintf("%srequest %q not found in the report, its SLOs can't be evaluated", prefix, name),
+					Violated: true,
+				})
+				continue
+			}
+			results = append(results, evaluateThresholds(fmt.Sprintf("%srequest %q", prefix, name), slos.PerRequest[name], request)...)
+		}
+	}
+	return results
+}
+
+func evaluateThresholds(subject string, thresholds sloThresholds, stats requestSummary) []sloResult {
+	var results []sloResult
+	check := func(threshold *float64, name string, actual float64, unit string, isMax bool) {
+		if threshold == nil {
+			return
+		}
+		violated, bound, violation := actual > *threshold, "at most", "above"
+		if !isMax {
+			violated, bound, violation = actual < *threshold, "at least", "below"
+		}
+		message := fmt.Sprintf("%s of %s is %s%s, within the SLO of %s %s%s", name, subject, formatNumber(actual), unit, bound, formatNumber(*threshold), unit)
+		if violated {
+			message = fmt.Sprintf("%s of %s is %s%s, %s the SLO of %s%s", name, subject, formatNumber(actual), unit, violation, formatNumber(*threshold), unit)
+		}
+		results = append(results, sloResult{Message: message, Violated: violated})
+	}
+	check(thresholds.MaxP95, "p95 response time", stats.P95, " ms", true)
+	check(thresholds.MaxP99, "p99 response time", stats.P99, " ms", true)
+	check(thresholds.MaxErrorPercentage, "error percentage", stats.ErrorPercentage, "%", true)
+	check(thresholds.MinThroughput, "throughput", stats.Throughput, " req/s", false)
+	return results
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// checkSlos evaluates the SLOs against the reports of a run. Returns the
+// messages about every threshold and an error if any is violated, or if there
+// is no report to evaluate them against.
+func checkSlos(slos *slos, reportDirs []string) ([]action_kit_api.Message, *action_kit_api.ActionKitError) {
+	summaries := readSimulationSummaries(reportDirs)
+	if len(summaries) == 0 {
+		return nil, &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Errored),
+			Title:  "SLOs not evaluated, Gatling didn't generate a report.",
+		}
+	}
+
+	results := evaluateSlos(slos, summaries)
+	violations := 0
+	for _, result := range results {
+		if result.Violated {
+			violations++
+		}
+	}
+	if violations == 0 {
+		return sloMessages(results), nil
+	}
+	title := "Gatling run violated an SLO."
+	if violations > 1 {
+		title = fmt.Sprintf("Gatling run violated %d SLOs.", violations)
+	}
+	return sloMessages(results), &action_kit_api.ActionKitError{
+		Status: extutil.Ptr(action_kit_api.Failed),
+		Title:  title,
+	}
+}
+
+// sloMessages turns the results into messages of the action, violations as
+// errors.
+func sloMessages(results []sloResult) []action_kit_api.Message {
+	messages := make([]action_kit_api.Message, 0, len(results))
+	for _, result := range results {
+		level, prefix := action_kit_api.Info, "SLO met: "
+		if result.Violated {
+			level, prefix = action_kit_api.Error, "SLO violated: "
+		}
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(level),
+			Message: prefix + result.Message,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extconversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSloThresholds(t *testing.T) {
+	thresholds, err := parseSloThresholds("p95=500ms, P99 = 1.5s, errors=1%, throughput=10")
+
+	require.NoError(t, err)
+	assert.InDelta(t, 500, *thresholds.MaxP95, 0.001)
+	assert.InDelta(t, 1500, *thresholds.MaxP99, 0.001)
+	assert.InDelta(t, 1, *thresholds.MaxErrorPercentage, 0.001)
+	assert.InDelta(t, 10, *thresholds.MinThroughput, 0.001)
+
+	thresholds, err = parseSloThresholds("p95=250")
+	require.NoError(t, err)
+	assert.InDelta(t, 250, *thresholds.MaxP95, 0.001)
+	assert.Nil(t, thresholds.MaxErrorPercentage)
+
+	for _, spec := range []string{"", "p95", "p50=100", "errors=many", "p99=soon"} {
+		_, err = parseSloThresholds(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func Test_parseSlos_from_the_action_config(t *testing.T) {
+	var config GatlingLoadTestRunConfig
+	require.NoError(t, extconversion.Convert(map[string]any{
+		"sloMaxP95":             300,
+		"sloMaxErrorPercentage": 0,
+		"sloPerRequest":         []map[string]string{{"key": "home", "value": "p99=1s"}},
+	}, &config))
+
+	slos, err := parseSlos(config)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 300, *slos.Global.MaxP95, 0.001)
+	assert.Nil(t, slos.Global.MaxP99)
+	assert.InDelta(t, 0, *slos.Global.MaxErrorPercentage, 0.001)
+	assert.InDelta(t, 1000, *slos.PerRequest["home"].MaxP99, 0.001)
+}
+
+func Test_parseSlos_without_thresholds(t *testing.T) {
+	slos, err := parseSlos(GatlingLoadTestRunConfig{SloMaxP95: new(0)})
+
+	require.NoError(t, err)
+	assert.Nil(t, slos)
+
+	_, err = parseSlos(GatlingLoadTestRunConfig{SloPerRequest: []map[string]string{{"key": "home", "value": "p42=1"}}})
+	assert.ErrorContains(t, err, `"home"`)
+}
+
+func Test_evaluateSlos(t *testing.T) {
+	summary := &simulationSummary{
+		Simulation: "basicsimulation",
+		Global:     requestSummary{Name: "Global", ErrorPercentage: 5, P95: 300, P99: 700, Throughput: 20},
+		Requests: []requestSummary{
+			{Name: "checkout / pay", ErrorPercentage: 10, P95: 500, Throughput: 10},
+			{Name: "home", P95: 60, Throughput: 10},
+		},
+	}
+	slos := &slos{
+		Global: sloThresholds{MaxP95: new(300.0), MaxErrorPercentage: new(1.0), MinThroughput: new(25.0)},
+		PerRequest: map[string]sloThresholds{
+			"home":           {MaxP95: new(100.0)},
+			"checkout / pay": {MaxErrorPercentage: new(10.0)},
+			"missing":        {MaxP95: new(100.0)},
+		},
+	}
+
+	results := evaluateSlos(slos, []*simulationSummary{summary})
+
+	assert.Equal(t, []sloResult{
+		{Message: "p95 response time of all requests is 300 ms, within the SLO of at most 300 ms"},
+		{Message: "error percentage of all requests is 5%, above the SLO of 1%", Violated: true},
+		{Message: "throughput of all requests is 20 req/s, below the SLO of 25 req/s", Violated: true},
+		{Message: `error percentage of request "checkout / pay" is 10%, within the SLO of at most 10%`},
+		{Message: `p95 response time of request "home" is 60 ms, within the SLO of at most 100 ms`},
+		{Message: `request "missing" not found in the report, its SLOs can't be evaluated`, Violated: true},
+	}, results)
+}
+
+func Test_checkSlos(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+
+	messages, err := checkSlos(&slos{Global: sloThresholds{MaxP99: new(500.0)}}, []string{reportDir})
+
+	require.NotNil(t, err)
+	assert.Equal(t, action_kit_api.Failed, *err.Status)
+	assert.Equal(t, "Gatling run violated an SLO.", err.Title)
+	require.Len(t, messages, 1)
+	assert.Equal(t, action_kit_api.Error, *messages[0].Level)
+	assert.Equal(t, "SLO violated: p99 response time of all requests is 700 ms, above the SLO of 500 ms", messages[0].Message)
+
+	messages, err = checkSlos(&slos{Global: sloThresholds{MaxP99: new(700.0)}}, []string{reportDir})
+	assert.Nil(t, err)
+	assert.Equal(t, action_kit_api.Info, *messages[0].Level)
+
+	_, err = checkSlos(&slos{Global: sloThresholds{MaxP99: new(700.0)}}, nil)
+	require.NotNil(t, err)
+	assert.Equal(t, action_kit_api.Errored, *err.Status)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/extstorage"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// uploadBudget bounds all uploads and simulation.log pushes of a Stop
+// together, well below the time the platform gives the call.
+const uploadBudget = extstorage.UploadBudget
+
+// uploadReport uploads the report folder reportDir, including simulation.log,
+// to the object storage and returns the messages pointing to it. A failed
+// upload doesn't fail the step, the report is still attached as artifact.
+func uploadReport(ctx context.Context, state *GatlingLoadTestRunState, reportDir string) []action_kit_api.Message {
+	name := filepath.Base(reportDir)
+	prefix := extstorage.ObjectPrefix(state.ExperimentKey, state.ExperimentExecutionId) + name + "/"
+	uploaded, err := extstorage.UploadDir(ctx, reportDir, prefix)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warn().Msgf("Upload of report %s cut short after %d files", name, uploaded)
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Upload of report %s to %s was cut short after %s, only %d files were uploaded. The report is still attached.", name, extstorage.Location(prefix), uploadBudget, uploaded),
+		}}
+	}
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to upload report %s", name)
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Failed to upload report %s to %s: %s", name, extstorage.Location(prefix), err),
+		}}
+	}
+	log.Info().Msgf("Uploaded %d files of report %s to %s", uploaded, name, extstorage.Location(prefix))
+
+	messages := []action_kit_api.Message{{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("Report %s uploaded to %s", name, extstorage.Location(prefix)),
+	}}
+	if link := extstorage.Link(prefix + "index.html"); link != "" && hasHtmlReport(reportDir) {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: fmt.Sprintf("[Open Uploaded Report %s](%s)", name, link),
+		})
+	}
+	return messages
+}
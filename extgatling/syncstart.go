@@ -0,0 +1,322 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extmetrics"
+	"github.com/steadybit/extension-gatling/exttracing"
+	extension_kit "github.com/steadybit/extension-kit"
+)
+
+// Load distribution modes of a synchronized start.
+const (
+	loadDistributionFull       = "full"
+	loadDistributionDistribute = "distribute"
+)
+
+// syncStart is the synchronized start of a local run, set if the run waits
+// for other locations before injecting load.
+type syncStart struct {
+	Locations int    `json:"locations"`
+	Timeout   int64  `json:"timeout"` // milliseconds
+	Source    string `json:"source"`
+	// whether the locations share the load by their weight instead of each
+	// running the full simulation
+	Distribute bool    `json:"distribute,omitempty"`
+	Weight     float64 `json:"weight,omitempty"`
+	// the barrier round the location joined and the instant it was released
+	// at, epoch millis
+	Round        int   `json:"round,omitempty"`
+	StartAt      int64 `json:"startAt,omitempty"`
+	SkewReported bool  `json:"skewReported,omitempty"`
+	// whether the location told the barrier when it started injecting
+	StartReported bool `json:"startReported,omitempty"`
+	// when the location arrived at the barrier, epoch millis, zero once it
+	// was released, and how many locations it last saw there
+	WaitingSince int64 `json:"waitingSince,omitempty"`
+	Arrived      int   `json:"arrived,omitempty"`
+}
+
+// parseSyncStart returns the synchronized start requested by the parameters,
+// nil if the run starts on its own.
+func parseSyncStart(params GatlingLoadTestRunConfig) (*syncStart, error) {
+	distribute := params.LoadDistribution == loadDistributionDistribute
+	if params.SyncLocations == nil || *params.SyncLocations <= 1 {
+		if distribute {
+			return nil, errors.New("distributing the load requires a synchronized start of at least two locations")
+		}
+		return nil, nil
+	}
+	if config.Config.AggregationUrl == "" {
+		return nil, errors.New("a synchronized start requires STEADYBIT_EXTENSION_AGGREGATION_URL to be set")
+	}
+	start := &syncStart{
+		Locations:  *params.SyncLocations,
+		Timeout:    time.Minute.Milliseconds(),
+		Source:     aggregationSource(),
+		Distribute: distribute,
+		Weight:     config.Config.LocationWeight,
+	}
+	if params.SyncTimeout != nil && *params.SyncTimeout > 0 {
+		start.Timeout = int64(*params.SyncTimeout)
+	}
+	return start, nil
+}
+
+// syncCommands splits the command of a run into compiling the simulation and
+// running it without compiling again.
+func syncCommands(command []string) (compile, run []string) {
+	compile = slices.Clone(command)
+	run = slices.Clone(command)
+	if i := slices.Index(command, "integration-test"); i >= 0 {
+		compile[i] = "test-compile"
+		run[i] = "gatling:test"
+	}
+	return compile, run
+}
+
+// compileSimulation compiles the simulation of a synchronized start in
+// Prepare and leaves the command to run it in the state.
+func compileSimulation(ctx context.Context, state *GatlingLoadTestRunState, executionRoot string) (messages []action_kit_api.Message, err error) {
+	_, span := exttracing.StartSpan(ctx, "gatling.run.prepare.compile")
+	defer func() { exttracing.End(span, err) }()
+
+	compile, run := syncCommands(state.Command)
+	log.Info().Msgf("Compiling the simulation with command: %s", strings.Join(compile, " "))
+	started := time.Now()
+	cmd := exec.Command(compile[0], compile[1:]...)
+	cmd.Dir = fmt.Sprintf("%v/gatling-maven-scaffold", executionRoot)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		stdOutToLog(lines)
+		return nil, extension_kit.ExtensionError{
+			Title:  "Failed to compile the simulation.",
+			Detail: new(strings.Join(lines[max(0, len(lines)-20):], "\n")),
+		}
+	}
+	state.Command = run
+	compiled := time.Since(started)
+	extmetrics.ObserveCompileDuration(compiled)
+	return []action_kit_api.Message{
+		markdownMessage(fmt.Sprintf("🛠️ Compiled the simulation in %s", compiled.Round(time.Second))),
+	}, nil
+}
+
+// arriveAtSyncStart arrives at the barrier of the synchronized start. The
+// run waits for the other locations on its status calls, see pollSyncStart.
+func arriveAtSyncStart(state *GatlingLoadTestRunState, now time.Time) ([]action_kit_api.Message, error) {
+	status, err := newBarrierClient(state.ExperimentKey, state.ExperimentExecutionId).arrive(state.Sync)
+	if err != nil {
+		return nil, err
+	}
+	state.Sync.Round = status.Round
+	state.Sync.WaitingSince = now.UnixMilli()
+	return waitingMessages(state, status), nil
+}
+
+// pollSyncStart asks the barrier whether all locations arrived and returns
+// the released round if they did. Fails once they didn't within the timeout
+// of the sync.
+func pollSyncStart(state *GatlingLoadTestRunState, now time.Time) (barrierStatus, bool, error) {
+	status, err := newBarrierClient(state.ExperimentKey, state.ExperimentExecutionId).get(state.Sync.Round)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to poll the barrier")
+	} else if status.StartAt != 0 {
+		state.Sync.StartAt = status.StartAt
+		state.Sync.WaitingSince = 0
+		return status, true, nil
+	}
+	timeout := time.Duration(state.Sync.Timeout) * time.Millisecond
+	if now.Sub(time.UnixMilli(state.Sync.WaitingSince)) > timeout {
+		return status, false, fmt.Errorf("only %d of %d locations reached the synchronized start within %s",
+			state.Sync.Arrived, state.Sync.Locations, timeout)
+	}
+	return status, false, nil
+}
+
+// waitingMessages tells how many locations the run still waits for, only if
+// that changed.
+func waitingMessages(state *GatlingLoadTestRunState, status barrierStatus) []action_kit_api.Message {
+	arrived := len(status.Arrivals)
+	if arrived == 0 || arrived == state.Sync.Arrived {
+		return nil
+	}
+	state.Sync.Arrived = arrived
+	if arrived >= state.Sync.Locations {
+		return nil
+	}
+	return []action_kit_api.Message{
+		markdownMessage(fmt.Sprintf("⏳ Waiting for %d of %d locations to start synchronized", state.Sync.Locations-arrived, state.Sync.Locations)),
+	}
+}
+
+// locationShare is the part of the load a location runs when the locations
+// distribute it. The index is the position of the location among all, sorted
+// by name, starting at 0.
+type locationShare struct {
+	Index  int
+	Count  int
+	Weight float64
+	Share  float64
+}
+
+// shareOf computes the share of source from the weights of all locations of
+// the released barrier.
+func shareOf(status barrierStatus, source string) locationShare {
+	sources := make([]string, 0, len(status.Arrivals))
+	var total float64
+	for name := range status.Arrivals {
+		sources = append(sources, name)
+		total += status.Weights[name]
+	}
+	sort.Strings(sources)
+	share := locationShare{Index: slices.Index(sources, source), Count: len(sources), Weight: status.Weights[source]}
+	if total > 0 {
+		share.Share = share.Weight / total
+	}
+	return share
+}
+
+// properties are the system properties simulations read their share from.
+func (s locationShare) properties() []string {
+	return []string{
+		fmt.Sprintf("-Dsteadybit.location.index=%d", s.Index),
+		fmt.Sprintf("-Dsteadybit.location.count=%d", s.Count),
+		fmt.Sprintf("-Dsteadybit.location.weight=%s", strconv.FormatFloat(s.Weight, 'f', -1, 64)),
+		fmt.Sprintf("-Dsteadybit.location.share=%s", strconv.FormatFloat(s.Share, 'f', -1, 64)),
+	}
+}
+
+func shareMessage(share locationShare) action_kit_api.Message {
+	return markdownMessage(fmt.Sprintf("⚖️ This location runs %s%% of the load, as location %d of %d",
+		formatNumber(share.Share*100), share.Index+1, share.Count))
+}
+
+// reportSyncStarted tells the coordinator when this location started
+// injecting, by the start of the run in the simulation.log header, once
+// Gatling wrote it. Spawning Maven and starting the JVM vary between the
+// locations, so the spawn time would hide most of the skew.
+func reportSyncStarted(state *GatlingLoadTestRunState, reportFolder string) {
+	if state.Sync == nil || state.Sync.StartAt == 0 || state.Sync.StartReported {
+		return
+	}
+	started := runStartOf(reportFolder)
+	if started == 0 {
+		return
+	}
+	_, err := newBarrierClient(state.ExperimentKey, state.ExperimentExecutionId).post(barrierRequest{
+		Source:  state.Sync.Source,
+		Round:   state.Sync.Round,
+		Started: started,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to report the start to the barrier")
+		return
+	}
+	state.Sync.StartReported = true
+}
+
+// runStartOf returns the earliest run start of the reports in reportFolder,
+// epoch millis, zero if Gatling didn't write a run header yet.
+func runStartOf(reportFolder string) int64 {
+	entries, err := os.ReadDir(reportFolder)
+	if err != nil {
+		return 0
+	}
+	var first int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		start, err := readRunStart(filepath.Join(reportFolder, entry.Name(), "simulation.log"))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Debug().Err(err).Msgf("Failed to read the run header of report %s", entry.Name())
+			}
+			continue
+		}
+		if start != 0 && (first == 0 || start < first) {
+			first = start
+		}
+	}
+	return first
+}
+
+// syncSkewMessages polls the barrier until all locations reported their
+// start, and then reports the skew between them once.
+func syncSkewMessages(state *GatlingLoadTestRunState) []action_kit_api.Message {
+	if state.Sync == nil || state.Sync.StartAt == 0 || state.Sync.SkewReported {
+		return nil
+	}
+	status, err := newBarrierClient(state.ExperimentKey, state.ExperimentExecutionId).get(state.Sync.Round)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to poll the barrier for the start skew")
+		return nil
+	}
+	messages := skewMessages(status)
+	if len(messages) > 0 {
+		state.Sync.SkewReported = true
+	}
+	return messages
+}
+
+// spread is the time between the first and the last of times, in
+// milliseconds.
+func spread(times map[string]int64) int64 {
+	var first, last int64
+	for _, t := range times {
+		if first == 0 || t < first {
+			first = t
+		}
+		last = max(last, t)
+	}
+	return last - first
+}
+
+// releaseMessage tells when the locations were released and how far apart
+// they arrived, which is the skew they would have started with.
+func releaseMessage(status barrierStatus) action_kit_api.Message {
+	return markdownMessage(fmt.Sprintf("🚦 %d locations start at %s, they were ready within %d ms of each other",
+		len(status.Arrivals), time.UnixMilli(status.StartAt).UTC().Format("15:04:05.000"), spread(status.Arrivals)))
+}
+
+// skewMessages reports how far apart the locations actually started once all
+// reported it, nil before.
+func skewMessages(status barrierStatus) []action_kit_api.Message {
+	if len(status.Starts) < status.Locations {
+		return nil
+	}
+	sources := make([]string, 0, len(status.Starts))
+	for source := range status.Starts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	var table strings.Builder
+	table.WriteString("| Location | Started |\n")
+	table.WriteString("|---|---:|\n")
+	for _, source := range sources {
+		_, _ = fmt.Fprintf(&table, "| %s | %+d ms |\n", markdownCell(source), status.Starts[source]-status.StartAt)
+	}
+	return []action_kit_api.Message{
+		markdownMessage(fmt.Sprintf("⏱️ Start skew between locations: %d ms", spread(status.Starts))),
+		markdownMessage(table.String()),
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/extwebhook"
+)
+
+// notifyWebhooks tells the webhooks about the run in the background, so
+// retries and slow deliveries don't hold up Stop. status and resultErr are the
+// outcome of the step, which may differ from the one of the exit code. resultErr
+// is nil for a step that didn't fail.
+func notifyWebhooks(state *GatlingLoadTestRunState, exitCode int, reportDirs []string, status string, resultErr *action_kit_api.ActionKitError) {
+	if !extwebhook.Enabled() {
+		return
+	}
+	results := webhookResults(state, exitCode, reportDirs)
+	detail := ""
+	if resultErr != nil {
+		detail = resultErr.Title
+	}
+	go func() {
+		for _, result := range results {
+			if result.Status != status {
+				result.Status = status
+				result.Detail = detail
+			}
+			extwebhook.Notify(result)
+		}
+	}()
+}
+
+// webhookResults builds the results to notify the webhooks about, one for each
+// report of the run, or a single one without simulation if Gatling didn't get
+// to write a report at all.
+func webhookResults(state *GatlingLoadTestRunState, exitCode int, reportDirs []string) []extwebhook.RunResult {
+	base := extwebhook.RunResult{
+		Action:        "Gatling",
+		ExperimentKey: state.ExperimentKey,
+		ExecutionId:   state.ExperimentExecutionId,
+		Status:        runStatus(exitCode),
+	}
+	if base.Status == extwebhook.StatusErrored {
+		base.Detail = fmt.Sprintf("Gatling run errored, exit-code %d", exitCode)
+	}
+	if len(reportDirs) == 0 {
+		return []extwebhook.RunResult{base}
+	}
+
+	results := make([]extwebhook.RunResult, 0, len(reportDirs))
+	for _, reportDir := range reportDirs {
+		result := base
+		result.Simulation = reportFolderSuffix.ReplaceAllString(filepath.Base(reportDir), "")
+		if summary, err := readSimulationSummary(reportDir); err == nil {
+			result.Summary = &extwebhook.Summary{
+				Requests:        summary.Global.Requests,
+				Ok:              summary.Global.Ok,
+				Ko:              summary.Global.Ko,
+				ErrorPercentage: summary.Global.ErrorPercentage,
+				Mean:            summary.Global.Mean,
+				P50:             summary.Global.P50,
+				P75:             summary.Global.P75,
+				P95:             summary.Global.P95,
+				P99:             summary.Global.P99,
+				Throughput:      summary.Global.Throughput,
+			}
+		}
+		assertions, err := readAssertions(reportDir)
+		if err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msgf("Failed to read the assertions of report %s", filepath.Base(reportDir))
+		}
+		result.Assertions = assertions
+		results = append(results, result)
+	}
+	return results
+}
+
+// runStatus maps the exit code of Gatling to the status reported to webhooks
+// and metrics.
+func runStatus(exitCode int) string {
+	switch exitCode {
+	case 0:
+		return extwebhook.StatusSucceeded
+	case 2:
+		return extwebhook.StatusFailed
+	case 130:
+		return extwebhook.StatusStopped
+	default:
+		return extwebhook.StatusErrored
+	}
+}
+
+// readAssertions reads the outcome of the simulation's assertions from the
+// js/assertions.json file Gatling generates alongside the HTML report.
+func readAssertions(reportDir string) ([]extwebhook.Assertion, error) {
+	content, err := os.ReadFile(filepath.Join(reportDir, "js", "assertions.json"))
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Assertions []struct {
+			Message     string          `json:"message"`
+			Result      bool            `json:"result"`
+			ActualValue json.RawMessage `json:"actualValue"`
+		} `json:"assertions"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	assertions := make([]extwebhook.Assertion, 0, len(file.Assertions))
+	for _, a := range file.Assertions {
+		assertions = append(assertions, extwebhook.Assertion{
+			Message:     a.Message,
+			Result:      a.Result,
+			ActualValue: actualValues(a.ActualValue),
+		})
+	}
+	return assertions, nil
+}
+
+// actualValues accepts both, a single value and the list of values Gatling
+// writes for assertions on several requests.
+func actualValues(raw json.RawMessage) []float64 {
+	var values []float64
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+	var value float64
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []float64{value}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extgatling
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extwebhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assertionsJson = `{
+  "simulation": "basicsimulation",
+  "assertions": [
+    {"path": "Global", "message": "Global: max of response time is less than 1000.0", "result": true, "actualValue": [900.0]},
+    {"path": "Global", "message": "Global: percentage of failed events is less than 1.0", "result": false, "actualValue": 5.0}
+  ]
+}`
+
+func Test_webhookResults_per_report(t *testing.T) {
+	reportDir := filepath.Join(t.TempDir(), "basicsimulation-20260101120000123")
+	writeFile(t, filepath.Join(reportDir, "js", "stats.json"), statsJson)
+	writeFile(t, filepath.Join(reportDir, "js", "assertions.json"), assertionsJson)
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	results := webhookResults(state, 2, []string{reportDir})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "ADM-1", results[0].ExperimentKey)
+	assert.Equal(t, 42, results[0].ExecutionId)
+	assert.Equal(t, "basicsimulation", results[0].Simulation)
+	assert.Equal(t, extwebhook.StatusFailed, results[0].Status)
+	assert.Equal(t, []extwebhook.Assertion{
+		{Message: "Global: max of response time is less than 1000.0", Result: true, ActualValue: []float64{900}},
+		{Message: "Global: percentage of failed events is less than 1.0", Result: false, ActualValue: []float64{5}},
+	}, results[0].Assertions)
+	require.NotNil(t, results[0].Summary)
+	assert.Equal(t, int64(200), results[0].Summary.Requests)
+	assert.InDelta(t, 300, results[0].Summary.P95, 0.001)
+}
+
+func Test_webhookResults_without_report(t *testing.T) {
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	results := webhookResults(state, 1, nil)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, extwebhook.StatusErrored, results[0].Status)
+	assert.Equal(t, "Gatling run errored, exit-code 1", results[0].Detail)
+	assert.Empty(t, results[0].Simulation)
+	assert.Nil(t, results[0].Summary)
+}
+
+func Test_notifyWebhooks_in_the_background(t *testing.T) {
+	received := make(chan extwebhook.RunResult, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result extwebhook.RunResult
+		_ = json.NewDecoder(r.Body).Decode(&result)
+		received <- result
+	}))
+	defer server.Close()
+	config.Config.WebhookUrls = []string{server.URL}
+	defer func() { config.Config.WebhookUrls = nil }()
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	notifyWebhooks(state, 0, nil, extwebhook.StatusFailed, &action_kit_api.ActionKitError{Title: "SLO exceeded"})
+
+	select {
+	case result := <-received:
+		assert.Equal(t, extwebhook.StatusFailed, result.Status)
+		assert.Equal(t, "SLO exceeded", result.Detail)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not notified")
+	}
+}
+
+func Test_notifyWebhooks_without_result_error(t *testing.T) {
+	received := make(chan extwebhook.RunResult, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result extwebhook.RunResult
+		_ = json.NewDecoder(r.Body).Decode(&result)
+		received <- result
+	}))
+	defer server.Close()
+	config.Config.WebhookUrls = []string{server.URL}
+	defer func() { config.Config.WebhookUrls = nil }()
+	state := &GatlingLoadTestRunState{ExperimentKey: "ADM-1", ExperimentExecutionId: 42}
+
+	// the status of the step differs from the one of the exit code without an error
+	notifyWebhooks(state, 1, nil, extwebhook.StatusStopped, nil)
+
+	select {
+	case result := <-received:
+		assert.Equal(t, extwebhook.StatusStopped, result.Status)
+		assert.Empty(t, result.Detail)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not notified")
+	}
+}
@@ -5,11 +5,17 @@ package extgatlingenterprise
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
 	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extmetrics"
+	"github.com/steadybit/extension-gatling/extstorage"
+	"github.com/steadybit/extension-gatling/exttracing"
+	"github.com/steadybit/extension-gatling/extwebhook"
 	"github.com/steadybit/extension-kit"
 	"github.com/steadybit/extension-kit/extbuild"
 	"github.com/steadybit/extension-kit/extutil"
@@ -20,9 +26,11 @@ type RunAction struct {
 }
 
 type RunState struct {
+	ActionExecutionId    uuid.UUID         `json:"actionExecutionId"`
 	ExperimentKey        string            `json:"experimentKey"`
 	ExecutionId          int               `json:"executionId"`
 	SimulationId         string            `json:"simulationId"`
+	SimulationName       string            `json:"simulationName"`
 	RunId                string            `json:"runId"`
 	LastState            int               `json:"lastState"`
 	SystemProperties     map[string]string `json:"systemProperties"`
@@ -103,7 +111,14 @@ func (f RunAction) Describe() action_kit_api.ActionDescription {
 	}
 }
 
-func (f RunAction) Prepare(_ context.Context, state *RunState, raw action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+func (f RunAction) Prepare(ctx context.Context, state *RunState, raw action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, raw.ExecutionId, "gatling.enterprise.run.prepare", exttracing.ExecutionContextAttributes(raw.ExecutionContext)...)
+	result, err := f.prepare(ctx, state, raw)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (f RunAction) prepare(_ context.Context, state *RunState, raw action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
 	simulationIds := raw.Target.Attributes["gatling.simulation.id"]
 	if len(simulationIds) == 0 {
 		return nil, extension_kit.ToError("No simulation id provided", nil)
@@ -111,7 +126,11 @@ func (f RunAction) Prepare(_ context.Context, state *RunState, raw action_kit_ap
 	if len(simulationIds) > 1 {
 		return nil, extension_kit.ToError("More than one simulation id provided", nil)
 	}
+	state.ActionExecutionId = raw.ExecutionId
 	state.SimulationId = simulationIds[0]
+	if names := raw.Target.Attributes["gatling.simulation.name"]; len(names) > 0 {
+		state.SimulationName = names[0]
+	}
 	if raw.ExecutionContext.ExecutionId != nil {
 		state.ExecutionId = *raw.ExecutionContext.ExecutionId
 	}
@@ -137,12 +156,20 @@ func (f RunAction) Prepare(_ context.Context, state *RunState, raw action_kit_ap
 	return nil, nil
 }
 
-func (f RunAction) Start(_ context.Context, state *RunState) (*action_kit_api.StartResult, error) {
-	runId, err := RunSimulation(state.SimulationId, fmt.Sprintf("Steadybit - %s - %d", state.ExperimentKey, state.ExecutionId), fmt.Sprintf("Executed by Steadybit Experiment %s, Execution %d", state.ExperimentKey, state.ExecutionId), state.SystemProperties, state.EnvironmentVariables)
+func (f RunAction) Start(ctx context.Context, state *RunState) (*action_kit_api.StartResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ActionExecutionId, "gatling.enterprise.run.start", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExecutionId)...)
+	result, err := f.start(ctx, state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (f RunAction) start(ctx context.Context, state *RunState) (*action_kit_api.StartResult, error) {
+	runId, err := RunSimulation(ctx, state.SimulationId, fmt.Sprintf("Steadybit - %s - %d", state.ExperimentKey, state.ExecutionId), fmt.Sprintf("Executed by Steadybit Experiment %s, Execution %d", state.ExperimentKey, state.ExecutionId), state.SystemProperties, state.EnvironmentVariables)
 	if err != nil {
 		return nil, extension_kit.ToError("Failed to run simulation", err)
 	}
 	state.RunId = *runId
+	extmetrics.RunStarted(actionId)
 
 	messages := []action_kit_api.Message{}
 	if config.Config.EnterpriseApiBaseUrl == config.DefaultEnterpriseApiBaseUrl {
@@ -168,10 +195,17 @@ func (f RunAction) Start(_ context.Context, state *RunState) (*action_kit_api.St
 	return result, nil
 }
 
-func (f RunAction) Status(_ context.Context, state *RunState) (*action_kit_api.StatusResult, error) {
+func (f RunAction) Status(ctx context.Context, state *RunState) (*action_kit_api.StatusResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ActionExecutionId, "gatling.enterprise.run.status", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExecutionId)...)
+	result, err := f.status(ctx, state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (f RunAction) status(ctx context.Context, state *RunState) (*action_kit_api.StatusResult, error) {
 	log.Info().Str("runId", state.RunId).Msg("Checking run status.")
 
-	run, err := GetRun(state.RunId)
+	run, err := GetRun(ctx, state.RunId)
 	if err != nil {
 		return nil, extension_kit.ToError("Failed to get run info", err)
 	}
@@ -267,12 +301,19 @@ func statusToString(status int) string {
 	}
 }
 
-func (f RunAction) Stop(_ context.Context, state *RunState) (*action_kit_api.StopResult, error) {
+func (f RunAction) Stop(ctx context.Context, state *RunState) (*action_kit_api.StopResult, error) {
+	ctx, span := exttracing.StartExecutionSpan(ctx, state.ActionExecutionId, "gatling.enterprise.run.stop", exttracing.ExperimentAttributes(state.ExperimentKey, state.ExecutionId)...)
+	result, err := f.stop(ctx, state)
+	exttracing.End(span, err)
+	return result, err
+}
+
+func (f RunAction) stop(ctx context.Context, state *RunState) (*action_kit_api.StopResult, error) {
 	if state.RunId == "" {
 		return nil, nil
 	}
 
-	run, err := GetRun(state.RunId)
+	run, err := GetRun(ctx, state.RunId)
 	if err != nil {
 		return nil, extension_kit.ToError("Failed to get run info", err)
 	}
@@ -295,9 +336,17 @@ func (f RunAction) Stop(_ context.Context, state *RunState) (*action_kit_api.Sto
 		}
 	}
 
+	if extstorage.Enabled() {
+		uploadCtx, cancelUpload := context.WithTimeout(context.Background(), extstorage.UploadBudget)
+		messages = append(messages, uploadRun(uploadCtx, state, run)...)
+		cancelUpload()
+	}
+	extwebhook.Notify(webhookResult(state, run))
+	extmetrics.RunFinished(actionId, runStatus(run))
+
 	if run.Status < 4 {
 		log.Info().Str("runId", state.RunId).Msgf("Stop run")
-		abortErr := StopRun(state.RunId)
+		abortErr := StopRun(ctx, state.RunId)
 		if abortErr != nil {
 			return nil, extension_kit.ToError("Failed to abort run", abortErr)
 		}
@@ -307,3 +356,72 @@ func (f RunAction) Stop(_ context.Context, state *RunState) (*action_kit_api.Sto
 
 	return &action_kit_api.StopResult{Messages: new(messages)}, nil
 }
+
+// uploadRun uploads the run result to the object storage. The public API of
+// Gatling Enterprise offers no download of the report itself, so the result with
+// status, timings and assertions is all there is to keep. The upload gives up
+// once ctx is done.
+func uploadRun(ctx context.Context, state *RunState, run *GatlingRunResponse) []action_kit_api.Message {
+	key := fmt.Sprintf("%srun-%s.json", extstorage.ObjectPrefix(state.ExperimentKey, state.ExecutionId), state.RunId)
+	content, err := json.MarshalIndent(run, "", "  ")
+	if err == nil {
+		err = extstorage.UploadBytes(ctx, key, content)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("runId", state.RunId).Msg("Failed to upload run result")
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Failed to upload the run result to %s: %s", extstorage.Location(key), err),
+		}}
+	}
+	message := fmt.Sprintf("Run result uploaded to %s", extstorage.Location(key))
+	if link := extstorage.Link(key); link != "" {
+		message = fmt.Sprintf("Run result uploaded to [%s](%s)", extstorage.Location(key), link)
+	}
+	return []action_kit_api.Message{{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: message,
+	}}
+}
+
+// webhookResult builds the result to notify the webhooks about. The public API
+// of Gatling Enterprise doesn't expose the statistics of a run, so there is no
+// summary.
+func webhookResult(state *RunState, run *GatlingRunResponse) extwebhook.RunResult {
+	assertions := make([]extwebhook.Assertion, 0, len(run.Assertions))
+	for _, assertion := range run.Assertions {
+		assertions = append(assertions, extwebhook.Assertion{
+			Message:     assertion.Message,
+			Result:      assertion.Result,
+			ActualValue: []float64{assertion.ActualValue},
+		})
+	}
+	simulation := state.SimulationName
+	if simulation == "" {
+		simulation = state.SimulationId
+	}
+	return extwebhook.RunResult{
+		Action:        "Gatling Enterprise",
+		ExperimentKey: state.ExperimentKey,
+		ExecutionId:   state.ExecutionId,
+		Simulation:    simulation,
+		Status:        runStatus(run),
+		Detail:        fmt.Sprintf("Simulation state: %s", statusToString(run.Status)),
+		Assertions:    assertions,
+	}
+}
+
+// runStatus maps the state of a run to the status reported to webhooks and
+// metrics. A run that is still going when Stop is called gets aborted.
+func runStatus(run *GatlingRunResponse) string {
+	switch {
+	case run.Error != "" || (run.Status >= 9 && run.Status <= 13):
+		return extwebhook.StatusErrored
+	case run.Status == 8:
+		return extwebhook.StatusFailed
+	case run.Status >= 4 && run.Status <= 6:
+		return extwebhook.StatusSucceeded
+	default:
+		return extwebhook.StatusStopped
+	}
+}
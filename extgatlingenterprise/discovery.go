@@ -83,8 +83,8 @@ func (d *gatlingEnterpriseSimulationDiscovery) DescribeAttributes() []discovery_
 	}
 }
 
-func (d *gatlingEnterpriseSimulationDiscovery) DiscoverTargets(_ context.Context) ([]discovery_kit_api.Target, error) {
-	simulations := GetSimulations()
+func (d *gatlingEnterpriseSimulationDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_api.Target, error) {
+	simulations := GetSimulations(ctx)
 	targets := make([]discovery_kit_api.Target, len(simulations))
 
 	for i, simulation := range simulations {
@@ -4,12 +4,18 @@ package extgatlingenterprise
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-gatling/extmetrics"
+	"github.com/steadybit/extension-gatling/exttracing"
 	"github.com/steadybit/extension-kit/extbuild"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"io"
 	"net/http"
 	"net/url"
@@ -56,7 +62,7 @@ type GatlingSimulation struct {
 	Build     GatlingSimulationBuild `json:"build"`
 }
 
-func GetSimulations() []GatlingSimulation {
+func GetSimulations(ctx context.Context) []GatlingSimulation {
 	var specification = config.Config
 	var apiToken = specification.EnterpriseApiToken
 	simulationsUrl, err := url.Parse(specification.EnterpriseApiBaseUrl)
@@ -67,7 +73,7 @@ func GetSimulations() []GatlingSimulation {
 
 	client := getClient()
 	simulationsUrl.Path += "/simulations"
-	req, err := http.NewRequest("GET", simulationsUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", simulationsUrl.String(), nil)
 	if err != nil {
 		return nil
 	}
@@ -75,7 +81,7 @@ func GetSimulations() []GatlingSimulation {
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
 
-	response, err := client.Do(req)
+	response, err := doRequest("GetSimulations", client, req)
 	if err != nil {
 		log.Error().Msgf("Failed to get simulations from gatling enterprise api. Got error: %s", err)
 		return nil
@@ -103,7 +109,7 @@ func GetSimulations() []GatlingSimulation {
 	return result
 }
 
-func RunSimulation(simulationId string, title string, description string, systemProperties map[string]string, environmentVariables map[string]string) (*string, error) {
+func RunSimulation(ctx context.Context, simulationId string, title string, description string, systemProperties map[string]string, environmentVariables map[string]string) (*string, error) {
 	var specification = config.Config
 	var apiToken = specification.EnterpriseApiToken
 	runSimulationUrl, err := url.Parse(specification.EnterpriseApiBaseUrl)
@@ -131,7 +137,7 @@ func RunSimulation(simulationId string, title string, description string, system
 	}
 
 	log.Debug().Str("url", runSimulationUrl.String()).Str("body", string(bodyBytes)).Msg("Starting gatling simulation....")
-	req, err := http.NewRequest("POST", runSimulationUrl.String(), bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", runSimulationUrl.String(), bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +146,7 @@ func RunSimulation(simulationId string, title string, description string, system
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
 
-	response, err := client.Do(req)
+	response, err := doRequest("RunSimulation", client, req)
 	if err != nil {
 		log.Error().Msgf("Failed to start simulation via gatling enterprise api. Got error: %s", err)
 		return nil, err
@@ -170,7 +176,7 @@ func RunSimulation(simulationId string, title string, description string, system
 	return new(result.RunId), nil
 }
 
-func GetRun(runId string) (*GatlingRunResponse, error) {
+func GetRun(ctx context.Context, runId string) (*GatlingRunResponse, error) {
 	var specification = config.Config
 	var apiToken = specification.EnterpriseApiToken
 	runUrl, err := url.Parse(specification.EnterpriseApiBaseUrl)
@@ -185,7 +191,7 @@ func GetRun(runId string) (*GatlingRunResponse, error) {
 	q.Add("run", runId)
 	runUrl.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", runUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", runUrl.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +199,7 @@ func GetRun(runId string) (*GatlingRunResponse, error) {
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
 
-	response, err := client.Do(req)
+	response, err := doRequest("GetRun", client, req)
 	if err != nil {
 		log.Error().Msgf("Failed to get run via gatling enterprise api. Got error: %s", err)
 		return nil, err
@@ -221,6 +227,29 @@ func GetRun(runId string) (*GatlingRunResponse, error) {
 	return new(result), nil
 }
 
+// doRequest sends the request in a span and records its duration and whether
+// it failed, either on the connection or with a status other than 200.
+func doRequest(operation string, client *http.Client, req *http.Request) (*http.Response, error) {
+	_, span := exttracing.StartSpan(req.Context(), "Gatling Enterprise "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+		))
+	start := time.Now()
+	response, err := client.Do(req)
+	failed := err != nil || response.StatusCode != 200
+	extmetrics.ObserveEnterpriseApiCall(operation, start, failed)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+		if failed {
+			span.SetStatus(codes.Error, response.Status)
+		}
+	}
+	exttracing.End(span, err)
+	return response, err
+}
+
 func getClient() *http.Client {
 	// A timeout bounds every Gatling Enterprise API call so a slow or unresponsive endpoint
 	// cannot block discovery, status checks or the run lifecycle indefinitely.
@@ -237,7 +266,7 @@ func getClient() *http.Client {
 	return &http.Client{Timeout: timeout}
 }
 
-func StopRun(runId string) error {
+func StopRun(ctx context.Context, runId string) error {
 	var specification = config.Config
 	var apiToken = specification.EnterpriseApiToken
 	abortRunUrl, err := url.Parse(specification.EnterpriseApiBaseUrl)
@@ -252,7 +281,7 @@ func StopRun(runId string) error {
 	q.Add("run", runId)
 	abortRunUrl.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("POST", abortRunUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", abortRunUrl.String(), nil)
 	if err != nil {
 		return err
 	}
@@ -261,7 +290,7 @@ func StopRun(runId string) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
 
-	response, err := client.Do(req)
+	response, err := doRequest("StopRun", client, req)
 	if err != nil {
 		log.Error().Msgf("Failed to abort run via gatling enterprise api. Got error: %s", err)
 		return err
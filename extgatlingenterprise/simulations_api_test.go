@@ -5,6 +5,7 @@
 package extgatlingenterprise
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/steadybit/extension-gatling/config"
@@ -63,7 +64,7 @@ func TestGetSimulations(t *testing.T) {
 	config.Config.EnterpriseApiToken = "test-token"
 
 	// Call the function under test
-	simulations := GetSimulations()
+	simulations := GetSimulations(context.Background())
 
 	// Verify the results
 	require.NotNil(t, simulations)
@@ -118,7 +119,7 @@ func TestGetSimulations_Error(t *testing.T) {
 			config.Config.EnterpriseApiToken = "test-token"
 
 			// Call the function under test
-			simulations := GetSimulations()
+			simulations := GetSimulations(context.Background())
 
 			// Verify the results
 			if tc.expectedNilValue {
@@ -183,7 +184,7 @@ func TestRunSimulation(t *testing.T) {
 	environmentVariables := map[string]string{
 		"env1": "envVal1",
 	}
-	runId, err := RunSimulation("sim-123", "Test Title", "Test Description", systemProperties, environmentVariables)
+	runId, err := RunSimulation(context.Background(), "sim-123", "Test Title", "Test Description", systemProperties, environmentVariables)
 
 	// Verify the results
 	require.NoError(t, err)
@@ -233,7 +234,7 @@ func TestRunSimulation_Error(t *testing.T) {
 			config.Config.EnterpriseApiToken = "test-token"
 
 			// Call the function under test
-			runId, err := RunSimulation("sim-123", "Test Title", "Test Description", nil, nil)
+			runId, err := RunSimulation(context.Background(), "sim-123", "Test Title", "Test Description", nil, nil)
 
 			// Verify the results
 			if tc.expectError {
@@ -295,7 +296,7 @@ func TestGetRun(t *testing.T) {
 	config.Config.EnterpriseApiToken = "test-token"
 
 	// Call the function under test
-	run, err := GetRun("run-123")
+	run, err := GetRun(context.Background(), "run-123")
 
 	// Verify the results
 	require.NoError(t, err)
@@ -359,7 +360,7 @@ func TestGetRun_Error(t *testing.T) {
 			config.Config.EnterpriseApiToken = "test-token"
 
 			// Call the function under test
-			run, err := GetRun("run-123")
+			run, err := GetRun(context.Background(), "run-123")
 
 			// Verify the results
 			if tc.expectError {
@@ -401,7 +402,7 @@ func TestStopRun(t *testing.T) {
 	config.Config.EnterpriseApiToken = "test-token"
 
 	// Call the function under test
-	err := StopRun("run-123")
+	err := StopRun(context.Background(), "run-123")
 
 	// Verify the results
 	require.NoError(t, err)
@@ -443,7 +444,7 @@ func TestStopRun_Error(t *testing.T) {
 			config.Config.EnterpriseApiToken = "test-token"
 
 			// Call the function under test
-			err := StopRun("run-123")
+			err := StopRun(context.Background(), "run-123")
 
 			// Verify the results
 			if tc.expectError {
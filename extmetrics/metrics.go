@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+// Package extmetrics holds the metrics about the extension itself, exposed on
+// /metrics next to the live metrics of the running simulations.
+package extmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// startupBuckets cover compiling a simulation and starting Gatling, which takes
+// from a few seconds to several minutes for large simulations.
+var startupBuckets = []float64{5, 10, 20, 30, 45, 60, 90, 120, 180, 300, 600}
+
+var (
+	runsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gatling_extension_runs_started_total",
+		Help: "Runs started, by action.",
+	}, []string{"action"})
+	runsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gatling_extension_runs_finished_total",
+		Help: "Runs stopped, by action and status: succeeded, failed, errored or stopped.",
+	}, []string{"action", "status"})
+	compileDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gatling_extension_compile_duration_seconds",
+		Help:    "Time from starting Maven until the simulation starts, spent compiling the simulation and starting Gatling.",
+		Buckets: startupBuckets,
+	})
+	timeToFirstRequest = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gatling_extension_time_to_first_request_seconds",
+		Help:    "Time from starting Maven until the first request of the simulation completed.",
+		Buckets: startupBuckets,
+	})
+	enterpriseApiDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gatling_extension_enterprise_api_request_duration_seconds",
+		Help:    "Duration of the requests to the Gatling Enterprise API, by operation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+	enterpriseApiErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gatling_extension_enterprise_api_errors_total",
+		Help: "Failed requests to the Gatling Enterprise API, by operation.",
+	}, []string{"operation"})
+)
+
+// RunStarted counts a run of the action started.
+func RunStarted(action string) {
+	runsStarted.WithLabelValues(action).Inc()
+}
+
+// RunFinished counts a run of the action stopped with the given status.
+func RunFinished(action, status string) {
+	runsFinished.WithLabelValues(action, status).Inc()
+}
+
+// ObserveCompileDuration records the time it took until a local simulation
+// started.
+func ObserveCompileDuration(d time.Duration) {
+	compileDuration.Observe(d.Seconds())
+}
+
+// ObserveTimeToFirstRequest records the time it took until the first request of
+// a local simulation completed.
+func ObserveTimeToFirstRequest(d time.Duration) {
+	timeToFirstRequest.Observe(d.Seconds())
+}
+
+// ObserveEnterpriseApiCall records a request to the Gatling Enterprise API
+// started at start, counting it as error if failed is set.
+func ObserveEnterpriseApiCall(operation string, start time.Time, failed bool) {
+	enterpriseApiDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if failed {
+		enterpriseApiErrors.WithLabelValues(operation).Inc()
+	}
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCounters(t *testing.T) {
+	RunStarted("test.action")
+	RunStarted("test.action")
+	RunFinished("test.action", "failed")
+
+	assert.InDelta(t, 2, testutil.ToFloat64(runsStarted.WithLabelValues("test.action")), 0.001)
+	assert.InDelta(t, 1, testutil.ToFloat64(runsFinished.WithLabelValues("test.action", "failed")), 0.001)
+	assert.InDelta(t, 0, testutil.ToFloat64(runsFinished.WithLabelValues("test.action", "succeeded")), 0.001)
+}
+
+func TestObserveEnterpriseApiCall(t *testing.T) {
+	ObserveEnterpriseApiCall("TestOperation", time.Now(), false)
+	ObserveEnterpriseApiCall("TestOperation", time.Now(), true)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(enterpriseApiDuration))
+	assert.InDelta(t, 1, testutil.ToFloat64(enterpriseApiErrors.WithLabelValues("TestOperation")), 0.001)
+}
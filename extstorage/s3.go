@@ -0,0 +1,139 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+// Package extstorage uploads the results of load tests to an S3-compatible
+// object storage, e.g. AWS S3 or MinIO.
+package extstorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-gatling/config"
+)
+
+// UploadBudget bounds all uploads of a Stop together, well below the time the
+// platform gives the call, so an unreachable storage can't block Stop.
+const UploadBudget = 60 * time.Second
+
+// Enabled tells whether reports are uploaded at all, which requires an
+// endpoint and a bucket to be configured.
+func Enabled() bool {
+	return config.Config.S3Endpoint != "" && config.Config.S3Bucket != ""
+}
+
+// ObjectPrefix renders the configured key layout for an execution. The layout
+// may contain the placeholders {experimentKey} and {executionId}, the prefix
+// returned always ends with a slash unless it is empty.
+func ObjectPrefix(experimentKey string, executionId int) string {
+	prefix := strings.NewReplacer(
+		"{experimentKey}", experimentKey,
+		"{executionId}", fmt.Sprintf("%d", executionId),
+	).Replace(config.Config.S3KeyLayout)
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// Location returns the s3:// URL of a key or prefix in the configured bucket.
+func Location(key string) string {
+	return fmt.Sprintf("s3://%s/%s", config.Config.S3Bucket, key)
+}
+
+// Link returns the public URL of a key, or an empty string if the bucket
+// isn't published under a known URL.
+func Link(key string) string {
+	baseUrl := strings.TrimSuffix(config.Config.S3PublicBaseUrl, "/")
+	if baseUrl == "" {
+		return ""
+	}
+	return baseUrl + "/" + key
+}
+
+// UploadDir uploads all files in dir, keeping their relative paths below
+// prefix, and returns the number of files uploaded. It stops once ctx is done,
+// returning the files uploaded so far and the error of ctx.
+func UploadDir(ctx context.Context, dir, prefix string) (int, error) {
+	client, err := newClient()
+	if err != nil {
+		return 0, err
+	}
+
+	uploaded := 0
+	err = filepath.WalkDir(dir, func(file string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		key := prefix + filepath.ToSlash(rel)
+		log.Debug().Msgf("Uploading %s to %s", file, Location(key))
+		if _, err := client.FPutObject(ctx, config.Config.S3Bucket, key, file, minio.PutObjectOptions{ContentType: contentType(key)}); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to upload %s: %w", rel, err)
+		}
+		uploaded++
+		return nil
+	})
+	return uploaded, err
+}
+
+// UploadBytes uploads content as the object key. It gives up once ctx is done.
+func UploadBytes(ctx context.Context, key string, content []byte) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Uploading %s", Location(key))
+	_, err = client.PutObject(ctx, config.Config.S3Bucket, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{ContentType: contentType(key)})
+	return err
+}
+
+func newClient() (*minio.Client, error) {
+	var creds *credentials.Credentials
+	if config.Config.S3AccessKeyId != "" {
+		creds = credentials.NewStaticV4(config.Config.S3AccessKeyId, config.Config.S3SecretAccessKey, "")
+	} else {
+		// without explicit keys, fall back to the AWS environment variables and
+		// the instance or pod identity
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.IAM{},
+		})
+	}
+	return minio.New(config.Config.S3Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: config.Config.S3UseSsl,
+		Region: config.Config.S3Region,
+	})
+}
+
+// contentType guesses the content type from the extension, so browsers render
+// HTML reports opened through a public link instead of downloading them.
+func contentType(key string) string {
+	if t := mime.TypeByExtension(path.Ext(key)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extstorage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectPrefix(t *testing.T) {
+	defer restoreConfig(config.Config)
+
+	config.Config.S3KeyLayout = "{experimentKey}/{executionId}/"
+	assert.Equal(t, "ADM-1/42/", ObjectPrefix("ADM-1", 42))
+
+	config.Config.S3KeyLayout = "/gatling/{experimentKey}-{executionId}"
+	assert.Equal(t, "gatling/ADM-1-42/", ObjectPrefix("ADM-1", 42))
+
+	config.Config.S3KeyLayout = ""
+	assert.Equal(t, "", ObjectPrefix("ADM-1", 42))
+}
+
+func TestLink(t *testing.T) {
+	defer restoreConfig(config.Config)
+
+	config.Config.S3PublicBaseUrl = ""
+	assert.Equal(t, "", Link("ADM-1/42/index.html"))
+
+	config.Config.S3PublicBaseUrl = "https://reports.example.com/"
+	assert.Equal(t, "https://reports.example.com/ADM-1/42/index.html", Link("ADM-1/42/index.html"))
+}
+
+func TestUploadDir(t *testing.T) {
+	defer restoreConfig(config.Config)
+	objects := fakeS3(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "js"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "simulation.log"), []byte("run"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "js", "stats.json"), []byte("{}"), 0644))
+
+	uploaded, err := UploadDir(context.Background(), dir, "ADM-1/42/report/")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, uploaded)
+	assert.Equal(t, map[string]string{
+		"/reports/ADM-1/42/report/simulation.log": "3",
+		"/reports/ADM-1/42/report/js/stats.json":  "2",
+	}, objects())
+}
+
+func TestUploadDir_stops_when_the_context_is_done(t *testing.T) {
+	defer restoreConfig(config.Config)
+	objects := fakeS3(t)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "simulation.log"), []byte("run"), 0644))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	uploaded, err := UploadDir(ctx, dir, "ADM-1/42/report/")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, uploaded)
+	assert.Empty(t, objects())
+}
+
+// fakeS3 points the configuration to a server accepting all uploads and returns
+// a function listing the sizes of the objects uploaded by path. Over plain HTTP
+// the client signs the payload in chunks, so the body isn't the content as is.
+func fakeS3(t *testing.T) func() map[string]string {
+	t.Helper()
+	var mu sync.Mutex
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		objects[r.URL.Path] = r.Header.Get("X-Amz-Decoded-Content-Length")
+		mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	config.Config.S3Endpoint = strings.TrimPrefix(server.URL, "http://")
+	config.Config.S3Bucket = "reports"
+	config.Config.S3Region = "us-east-1"
+	config.Config.S3AccessKeyId = "access"
+	config.Config.S3SecretAccessKey = "secret"
+	config.Config.S3UseSsl = false
+	return func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return objects
+	}
+}
+
+func restoreConfig(spec config.Specification) {
+	config.Config = spec
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+// Package exttracing traces the calls of the actions and the requests to the
+// Gatling Enterprise API with OpenTelemetry.
+package exttracing
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extsignals"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/steadybit/extension-gatling"
+
+// Attribute keys of the spans.
+const (
+	ExecutionIdKey           = attribute.Key("steadybit.execution_id")
+	ExperimentKeyKey         = attribute.Key("steadybit.experiment_key")
+	ExperimentExecutionIdKey = attribute.Key("steadybit.experiment_execution_id")
+)
+
+// Init exports the spans via OTLP over HTTP if tracing is enabled. Without,
+// the spans go to the no-op provider OpenTelemetry defaults to. The exporter
+// is configured with the standard OTEL_EXPORTER_OTLP_* environment variables.
+func Init() {
+	if !config.Config.TracingEnabled {
+		return
+	}
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create the OTLP trace exporter, tracing is disabled")
+		return
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "steadybit-extension-gatling"),
+			attribute.String("service.version", extbuild.GetSemverVersionStringOrUnknown()),
+		),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to detect all resource attributes for tracing")
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	extsignals.AddSignalHandler(extsignals.SignalHandler{
+		Handler: func(_ os.Signal) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := provider.Shutdown(ctx); err != nil {
+				log.Warn().Err(err).Msg("Failed to flush the remaining spans")
+			}
+		},
+		Order: extsignals.OrderStopCustom,
+		Name:  "exttracing",
+	})
+	log.Info().Msg("Tracing via OTLP enabled")
+}
+
+// StartExecutionSpan starts a span for a call of the action execution
+// executionId. The calls of an execution are separate requests, so the spans
+// are tied together by their trace id instead, which is the execution id.
+func StartExecutionSpan(ctx context.Context, executionId uuid.UUID, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	var spanId trace.SpanID
+	copy(spanId[:], executionId[:8])
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID(executionId),
+		SpanID:     spanId,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	attributes = append(attributes, ExecutionIdKey.String(executionId.String()))
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// ExperimentAttributes identify the experiment execution the action runs in.
+func ExperimentAttributes(experimentKey string, experimentExecutionId int) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ExperimentKeyKey.String(experimentKey),
+		ExperimentExecutionIdKey.Int(experimentExecutionId),
+	}
+}
+
+// ExecutionContextAttributes identify the experiment execution of a prepare
+// request, before it is kept in the action state.
+func ExecutionContextAttributes(executionContext *action_kit_api.ExecutionContext) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	if executionContext == nil {
+		return attributes
+	}
+	if executionContext.ExperimentKey != nil {
+		attributes = append(attributes, ExperimentKeyKey.String(*executionContext.ExperimentKey))
+	}
+	if executionContext.ExecutionId != nil {
+		attributes = append(attributes, ExperimentExecutionIdKey.Int(*executionContext.ExecutionId))
+	}
+	return attributes
+}
+
+// StartSpan starts a span as child of the span in ctx, if any.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End ends the span, marking it as failed if err is set.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package exttracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartExecutionSpan_uses_the_execution_id_as_trace_id(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	executionId := uuid.New()
+
+	ctx, span := StartExecutionSpan(context.Background(), executionId, "gatling.run.start", ExperimentAttributes("ADM-1", 42)...)
+	_, child := StartSpan(ctx, "child")
+	End(child, errors.New("boom"))
+	End(span, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "gatling.run.start", spans[1].Name())
+	assert.Equal(t, trace.TraceID(executionId), spans[1].SpanContext().TraceID())
+	assert.Contains(t, spans[1].Attributes(), ExperimentKeyKey.String("ADM-1"))
+	assert.Contains(t, spans[1].Attributes(), ExecutionIdKey.String(executionId.String()))
+}
+
+func TestStartExecutionSpan_links_the_calls_of_an_execution(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	executionId := uuid.New()
+
+	_, prepare := StartExecutionSpan(context.Background(), executionId, "gatling.run.prepare")
+	End(prepare, nil)
+	_, stop := StartExecutionSpan(context.Background(), executionId, "gatling.run.stop")
+	End(stop, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].Parent().SpanID(), spans[1].Parent().SpanID())
+}
+
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
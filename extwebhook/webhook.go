@@ -0,0 +1,180 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+// Package extwebhook notifies the configured webhooks about the results of
+// load test runs.
+package extwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/steadybit/extension-kit/extbuild"
+)
+
+// Statuses of a run as reported to the webhooks.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusErrored   = "errored"
+	StatusStopped   = "stopped"
+)
+
+// Payload formats, selected by config.Config.WebhookFormat.
+const (
+	formatJson  = "json"
+	formatSlack = "slack"
+)
+
+const requestTimeout = 10 * time.Second
+
+// retryDelay is the delay before the first retry, it doubles with every retry.
+// A var so tests don't have to wait.
+var retryDelay = time.Second
+
+// RunResult is the JSON payload posted to the webhooks.
+type RunResult struct {
+	Action        string      `json:"action"`
+	ExperimentKey string      `json:"experimentKey"`
+	ExecutionId   int         `json:"executionId"`
+	Simulation    string      `json:"simulation"`
+	Status        string      `json:"status"`
+	Detail        string      `json:"detail,omitempty"`
+	Assertions    []Assertion `json:"assertions"`
+	Summary       *Summary    `json:"summary,omitempty"`
+}
+
+// Assertion is the outcome of one assertion of the simulation.
+type Assertion struct {
+	Message     string    `json:"message"`
+	Result      bool      `json:"result"`
+	ActualValue []float64 `json:"actualValue"`
+}
+
+// Summary holds the global statistics of a run, response times are in
+// milliseconds.
+type Summary struct {
+	Requests        int64   `json:"requests"`
+	Ok              int64   `json:"ok"`
+	Ko              int64   `json:"ko"`
+	ErrorPercentage float64 `json:"errorPercentage"`
+	Mean            float64 `json:"mean"`
+	P50             float64 `json:"p50"`
+	P75             float64 `json:"p75"`
+	P95             float64 `json:"p95"`
+	P99             float64 `json:"p99"`
+	Throughput      float64 `json:"throughput"`
+}
+
+// Enabled tells whether any webhook is configured.
+func Enabled() bool {
+	for _, url := range config.Config.WebhookUrls {
+		if strings.TrimSpace(url) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify posts the result to all configured webhooks in the background, so a
+// slow webhook doesn't hold up Stop. Failures are only logged.
+func Notify(result RunResult) {
+	for _, url := range config.Config.WebhookUrls {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		go func() {
+			if err := send(url, result); err != nil {
+				log.Warn().Err(err).Msgf("Failed to notify webhook %s", url)
+			}
+		}()
+	}
+}
+
+// send posts the result to url, retrying on connection errors, 429 and 5xx
+// responses.
+func send(url string, result RunResult) error {
+	body, err := payload(result)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		retryable, err := post(client, url, body)
+		if err == nil {
+			log.Debug().Msgf("Notified webhook %s about execution %d", url, result.ExecutionId)
+			return nil
+		}
+		if !retryable || attempt >= config.Config.WebhookRetries {
+			return err
+		}
+		log.Debug().Err(err).Msgf("Retrying webhook %s in %s", url, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func post(client *http.Client, url string, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("steadybit-extension-gatling/%s", extbuild.GetSemverVersionStringOrUnknown()))
+	response, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	retryable = response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook responded with status %s", response.Status)
+}
+
+func payload(result RunResult) ([]byte, error) {
+	if config.Config.WebhookFormat == formatSlack {
+		return json.Marshal(map[string]string{"text": slackText(result)})
+	}
+	if result.Assertions == nil {
+		result.Assertions = []Assertion{}
+	}
+	return json.Marshal(result)
+}
+
+// slackText renders the result as message in Slack's mrkdwn, which is what
+// Slack's incoming webhooks and most chat tools accepting Slack payloads expect.
+func slackText(result RunResult) string {
+	var sb strings.Builder
+	icon := ":white_check_mark:"
+	if result.Status != StatusSucceeded {
+		icon = ":x:"
+	}
+	fmt.Fprintf(&sb, "%s *%s %s* - experiment %s, execution %d", icon, result.Action, result.Status, result.ExperimentKey, result.ExecutionId)
+	if result.Simulation != "" {
+		fmt.Fprintf(&sb, ", simulation `%s`", result.Simulation)
+	}
+	if result.Detail != "" {
+		fmt.Fprintf(&sb, "\n%s", result.Detail)
+	}
+	for _, assertion := range result.Assertions {
+		icon := ":white_check_mark:"
+		if !assertion.Result {
+			icon = ":x:"
+		}
+		fmt.Fprintf(&sb, "\n%s %s", icon, assertion.Message)
+	}
+	if s := result.Summary; s != nil {
+		fmt.Fprintf(&sb, "\n%d requests, %.2f%% errors, %.1f req/s, p95 %.0f ms, p99 %.0f ms", s.Requests, s.ErrorPercentage, s.Throughput, s.P95, s.P99)
+	}
+	return sb.String()
+}
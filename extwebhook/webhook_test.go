@@ -0,0 +1,122 @@
+/*
+ * Copyright 2026 steadybit GmbH. All rights reserved.
+ */
+
+package extwebhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-gatling/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var result = RunResult{
+	Action:        "Gatling",
+	ExperimentKey: "ADM-1",
+	ExecutionId:   42,
+	Simulation:    "basicsimulation",
+	Status:        StatusFailed,
+	Assertions: []Assertion{
+		{Message: "Global: max of response time is less than 50.0", Result: false, ActualValue: []float64{120}},
+	},
+	Summary: &Summary{Requests: 100, Ko: 5, ErrorPercentage: 5, P95: 80, P99: 110, Throughput: 10},
+}
+
+func TestSend_posts_the_json_payload(t *testing.T) {
+	defer restoreConfig(config.Config)
+	config.Config.WebhookFormat = "json"
+	var received RunResult
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	require.NoError(t, send(server.URL, result))
+
+	assert.Equal(t, result, received)
+}
+
+func TestSend_posts_a_slack_payload(t *testing.T) {
+	defer restoreConfig(config.Config)
+	config.Config.WebhookFormat = "slack"
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	require.NoError(t, send(server.URL, result))
+
+	assert.Equal(t, ":x: *Gatling failed* - experiment ADM-1, execution 42, simulation `basicsimulation`\n"+
+		":x: Global: max of response time is less than 50.0\n"+
+		"100 requests, 5.00% errors, 10.0 req/s, p95 80 ms, p99 110 ms", received["text"])
+}
+
+func TestSend_retries_server_errors(t *testing.T) {
+	defer restoreConfig(config.Config)
+	defer useRetryDelay(time.Millisecond)()
+	config.Config.WebhookRetries = 3
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, send(server.URL, result))
+
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestSend_gives_up_after_the_retries(t *testing.T) {
+	defer restoreConfig(config.Config)
+	defer useRetryDelay(time.Millisecond)()
+	config.Config.WebhookRetries = 2
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	require.Error(t, send(server.URL, result))
+
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestSend_does_not_retry_client_errors(t *testing.T) {
+	defer restoreConfig(config.Config)
+	defer useRetryDelay(time.Millisecond)()
+	config.Config.WebhookRetries = 3
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	require.Error(t, send(server.URL, result))
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func useRetryDelay(delay time.Duration) func() {
+	previous := retryDelay
+	retryDelay = delay
+	return func() { retryDelay = previous }
+}
+
+func restoreConfig(spec config.Specification) {
+	config.Config = spec
+}
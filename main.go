@@ -5,7 +5,11 @@
 package main
 
 import (
+	"net/http"
+
 	_ "github.com/KimMachineGun/automemlimit" // By default, it sets `GOMEMLIMIT` to 90% of cgroup's memory limit.
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
@@ -14,6 +18,7 @@ import (
 	"github.com/steadybit/extension-gatling/config"
 	"github.com/steadybit/extension-gatling/extgatling"
 	"github.com/steadybit/extension-gatling/extgatlingenterprise"
+	"github.com/steadybit/extension-gatling/exttracing"
 	"github.com/steadybit/extension-kit/extbuild"
 	"github.com/steadybit/extension-kit/exthealth"
 	"github.com/steadybit/extension-kit/exthttp"
@@ -31,15 +36,27 @@ func main() {
 
 	config.ParseConfiguration()
 	config.ValidateConfiguration()
+	exttracing.Init()
 
 	action_kit_sdk.RegisterAction(extgatling.NewGatlingLoadTestRunAction())
+	action_kit_sdk.RegisterAction(extgatling.NewGatlingCheckAction())
+	action_kit_sdk.RegisterAction(extgatling.NewGatlingMergeAction())
 	discovery_kit_sdk.Register(extgatling.NewDiscovery())
 	if config.Config.EnterpriseApiToken != "" {
 		discovery_kit_sdk.Register(extgatlingenterprise.NewDiscovery())
 		action_kit_sdk.RegisterAction(extgatlingenterprise.NewGatlingEnterpriseRunAction())
 	}
 
+	extgatling.PrepareRunCgroups()
+
 	exthttp.RegisterRevisionedHandler("/", getExtensionList)
+	exthttp.RegisterHttpHandler(extgatling.ReportsPath, extgatling.ServeReports)
+	http.Handle(extgatling.AggregationPath, exthttp.PanicRecovery(http.HandlerFunc(extgatling.ServeAggregation)))
+	exthttp.RegisterHttpHandler(extgatling.BarrierPath, extgatling.ServeBarrier)
+	prometheus.MustRegister(extgatling.NewLiveMetricsCollector())
+	exthttp.RegisterHttpHandlerWithLogLevel("/metrics", serveMetrics, zerolog.DebugLevel)
+	extgatling.StartOtlpMetricsExport()
+	extgatling.StartReportPruning()
 
 	extsignals.ActivateSignalHandlers()
 	action_kit_sdk.RegisterCoverageEndpoints()
@@ -62,3 +79,11 @@ func getExtensionList() ExtensionListResponse {
 		DiscoveryList: discovery_kit_sdk.GetDiscoveryList(),
 	}
 }
+
+// metricsHandler serves the default Prometheus registry. Compression is left to
+// exthttp, which gzips all responses.
+var metricsHandler = promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{DisableCompression: true})
+
+func serveMetrics(w http.ResponseWriter, r *http.Request, _ []byte) {
+	metricsHandler.ServeHTTP(w, r)
+}